@@ -0,0 +1,22 @@
+package registry
+
+import (
+	"backend_base_app/application"
+	cfg "backend_base_app/config/env"
+	"backend_base_app/controller/auditexportcontroller"
+	"backend_base_app/gateway/apibaseappgateway"
+	"flag"
+)
+
+// AuditExport verify the audit chain, `go run main.go audit_export <file>` also export it
+func AuditExport() func() application.RegistryContract {
+	return func() application.RegistryContract {
+		//register config
+		config := cfg.NewViperConfig()
+
+		return &auditexportcontroller.Controller{
+			DataSource: apibaseappgateway.NewGateWayApiBaseApp(config),
+			OutputPath: flag.Arg(1),
+		}
+	}
+}
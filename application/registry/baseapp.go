@@ -7,6 +7,8 @@ import (
 	"backend_base_app/controller/apibaseappcontroller"
 	"backend_base_app/gateway/apibaseappgateway"
 	"backend_base_app/infrastructure/server"
+	"backend_base_app/lib/core/jwtkey"
+	"time"
 )
 
 type baseapp struct {
@@ -22,17 +24,42 @@ func ApiBaseApp() func() application.RegistryContract {
 		// register webhook
 		// TODO future
 
-		httpHandler := server.NewGinHTTPHandlerDefault(config.GetString("api_app_base.api_url"))
+		var allowOrigins []string
+		if err := config.UnmarshalKey("cors.allow_origins", &allowOrigins); err != nil {
+			panic(err)
+		}
+
+		httpHandler := server.NewGinHTTPHandler(config.GetString("api_app_base.api_url"), allowOrigins)
 
 		// datasource := apibaseappgateway.NewGateWayApiBaseApp(config)
 
+		keySet, err := jwtkey.LoadKeySet(newJwtKeyConfig(config))
+		if err != nil {
+			panic(err)
+		}
+
 		return &baseapp{
 			GinHTTPHandler: &httpHandler,
 			Controller: &apibaseappcontroller.Controller{
-				Router:     httpHandler.Router,
-				Config:     config,
-				DataSource: apibaseappgateway.NewGateWayApiBaseApp(config),
+				Router:        httpHandler.Router,
+				Config:        config,
+				DataSource:    apibaseappgateway.NewGateWayApiBaseApp(config),
+				KeySet:        keySet,
+				RefreshKeySet: jwtkey.NewSecretKeySet(config.GetString("api_app_base.refresh_secret_token")),
 			},
 		}
 	}
 }
+
+// newJwtKeyConfig read jwt_key section, without keys the access token keep using the shared secret
+func newJwtKeyConfig(config cfg.Config) jwtkey.Config {
+	var keyConfig jwtkey.Config
+	if err := config.UnmarshalKey("jwt_key", &keyConfig); err != nil {
+		panic(err)
+	}
+
+	keyConfig.Secret = config.GetString("api_app_base.secret_token")
+	keyConfig.TokenLifetime = time.Duration(config.GetInt("api_app_base.token_confidentiality_minute")) * time.Minute
+
+	return keyConfig
+}
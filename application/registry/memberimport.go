@@ -0,0 +1,29 @@
+package registry
+
+import (
+	"backend_base_app/application"
+	cfg "backend_base_app/config/env"
+	"backend_base_app/controller/memberimportcontroller"
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway/apibaseappgateway"
+	"flag"
+)
+
+// MemberImport create the members of a file, `go run main.go member_import <file> dry-run` only validate it
+func MemberImport() func() application.RegistryContract {
+	return func() application.RegistryContract {
+		//register config
+		config := cfg.NewViperConfig()
+
+		return &memberimportcontroller.Controller{
+			DataSource: apibaseappgateway.NewGateWayApiBaseApp(config),
+			Config: entity.MemberImportConfig{
+				MaxSize:   int64(config.GetInt("member_import.max_size_kb")) * 1024,
+				MaxRows:   config.GetInt("member_import.max_rows"),
+				BatchSize: config.GetInt("member_import.batch_size"),
+			}.WithDefault(),
+			InputPath: flag.Arg(1),
+			DryRun:    flag.Arg(2) == "dry-run",
+		}
+	}
+}
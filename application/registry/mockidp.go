@@ -0,0 +1,48 @@
+package registry
+
+import (
+	"backend_base_app/application"
+	cfg "backend_base_app/config/env"
+	"backend_base_app/controller"
+	"backend_base_app/controller/mockidpcontroller"
+	"backend_base_app/infrastructure/server"
+	"backend_base_app/lib/core/jwtkey"
+	"crypto/rand"
+	"crypto/rsa"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type mockidp struct {
+	*server.GinHTTPHandler
+	controller.Controller
+}
+
+// MockIdp is a local OpenID provider to try the oidc login, the signing key is created on every start
+func MockIdp() func() application.RegistryContract {
+	return func() application.RegistryContract {
+		//register config
+		config := cfg.NewViperConfig()
+
+		httpHandler := server.NewGinHTTPHandlerDefault(config.GetString("mock_idp.address"))
+
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+
+		return &mockidp{
+			GinHTTPHandler: &httpHandler,
+			Controller: &mockidpcontroller.Controller{
+				Router: httpHandler.Router,
+				Config: config,
+				KeySet: jwtkey.NewKeySet(&jwtkey.Key{
+					Kid:        "mock-idp",
+					Method:     jwt.SigningMethodRS256,
+					PrivateKey: privateKey,
+					PublicKey:  &privateKey.PublicKey,
+				}),
+			},
+		}
+	}
+}
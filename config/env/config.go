@@ -12,6 +12,7 @@ type Config interface {
 	GetUInt64(key string) uint64
 	GetFloat64(key string) float64
 	GetBool(key string) bool
+	UnmarshalKey(key string, rawVal interface{}) error
 	Init()
 }
 
@@ -54,6 +55,11 @@ func (v *viperConfig) GetBool(key string) bool {
 	return viper.GetBool(key)
 }
 
+// UnmarshalKey decode a nested section into a struct using its mapstructure tags
+func (v *viperConfig) UnmarshalKey(key string, rawVal interface{}) error {
+	return viper.UnmarshalKey(key, rawVal)
+}
+
 func NewViperConfig() Config {
 	v := &viperConfig{}
 	v.Init()
@@ -0,0 +1,133 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/apikey/v1/createapikeyv1"
+	"backend_base_app/usecase/apikey/v1/getallapikeyv1"
+	"backend_base_app/usecase/apikey/v1/revokeapikeyv1"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiBaseAppApiKeyCreate return the plain key once, only its hash is stored
+func ApiBaseAppApiKeyCreate(r *Controller) gin.HandlerFunc {
+	var inputPort = createapikeyv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		var req entity.CreateApiKeyReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		req.CreatedBy = claims.Subject
+		req.GrantedPermissions = r.getGrantedPermissionsFromContext(c)
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{Action: entity.AuditActionApiKeyCreate, Metadata: map[string]string{"name": req.Name}, Err: err}
+		if err == nil {
+			auditEvent.Metadata["api_key_id"] = res.ID
+			auditEvent.Changes = entity.NewAuditChanges(nil, res.ApiKeyData)
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
+
+func ApiBaseAppApiKeyFindAll(r *Controller) gin.HandlerFunc {
+	var inputPort = getallapikeyv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.BaseReqFind
+		if err := c.BindQuery(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		var reqValue entity.ApiKeyDataFind
+		c.BindQuery(&reqValue)
+		req.Value = reqValue
+
+		sortByParams := make(map[string]interface{})
+		for key, value := range c.Request.URL.Query() {
+			if strings.HasPrefix(key, "sort_by_") {
+				trimmedKey := strings.TrimPrefix(key, "sort_by_")
+				sortByParams[trimmedKey] = value
+			}
+		}
+		req.SortBy = sortByParams
+
+		res, count, err := inputPort.Execute(ctx, req)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		finalResponse := req.ToResponse(res, count)
+
+		r.Helper.SendSuccess(c, "Success", finalResponse, traceID)
+	}
+}
+
+func ApiBaseAppApiKeyRevoke(r *Controller) gin.HandlerFunc {
+	var inputPort = revokeapikeyv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		err := inputPort.Execute(ctx, c.Param("id"))
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:   entity.AuditActionApiKeyRevoke,
+			Metadata: map[string]string{"api_key_id": c.Param("id")},
+			Err:      err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
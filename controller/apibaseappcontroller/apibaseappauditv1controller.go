@@ -0,0 +1,60 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/getallauditeventv1"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiBaseAppAuditEventFindAll return the audit log, newest first unless sort_by_ is given
+func ApiBaseAppAuditEventFindAll(r *Controller) gin.HandlerFunc {
+	var inputPort = getallauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.BaseReqFind
+		if err := c.BindQuery(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		var reqValue entity.AuditEventDataFind
+		if err := c.BindQuery(&reqValue); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		req.Value = reqValue
+
+		sortByParams := make(map[string]interface{})
+		for key, value := range c.Request.URL.Query() {
+			if strings.HasPrefix(key, "sort_by_") {
+				trimmedKey := strings.TrimPrefix(key, "sort_by_")
+				sortByParams[trimmedKey] = value
+			}
+		}
+		req.SortBy = sortByParams
+
+		res, count, err := inputPort.Execute(ctx, req)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		finalResponse := req.ToResponse(res, count)
+
+		r.Helper.SendSuccess(c, "Success", finalResponse, traceID)
+	}
+}
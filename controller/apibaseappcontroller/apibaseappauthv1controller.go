@@ -5,16 +5,31 @@ import (
 	"backend_base_app/domain/entity"
 	"backend_base_app/shared/log"
 	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
 	"backend_base_app/usecase/authorization/v1/authmemberv1"
-	"backend_base_app/usecase/member/v1/getmemberv1"
-	"encoding/json"
+	"backend_base_app/usecase/authorization/v1/changepasswordv1"
+	"backend_base_app/usecase/authorization/v1/createsessionv1"
+	"backend_base_app/usecase/authorization/v1/forgotpasswordv1"
+	"backend_base_app/usecase/authorization/v1/getallsessionv1"
+	"backend_base_app/usecase/authorization/v1/logoutallmemberv1"
+	"backend_base_app/usecase/authorization/v1/logoutmemberv1"
+	"backend_base_app/usecase/authorization/v1/refreshauthmemberv1"
+	"backend_base_app/usecase/authorization/v1/resetpasswordv1"
+	"backend_base_app/usecase/authorization/v1/revokesessionv1"
+	"backend_base_app/usecase/verification/v1/confirmverificationv1"
+	"backend_base_app/usecase/verification/v1/requestverificationv1"
 	"fmt"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 func ApiBaseAppAuthMember(r *Controller) gin.HandlerFunc {
 	var inputPort = authmemberv1.NewUsecase(r.DataSource)
+	var sessionInputPort = createsessionv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
 
 	return func(c *gin.Context) {
 		traceID := util.GenerateID()
@@ -28,28 +43,193 @@ func ApiBaseAppAuthMember(r *Controller) gin.HandlerFunc {
 			return
 		}
 
-		fmt.Println("TAG LOGIN REQUEST => ", req)
+		req.UserAgent = c.Request.UserAgent()
+		req.IpAddress = c.ClientIP()
+		req.LoginProtection = r.loginProtectionConfig()
+		req.RequiredVerification = r.requiredVerification(ctx)
+
+		log.Info(ctx, "login request username %s device %s", req.Username, req.DeviceId)
 
 		res, err := inputPort.Execute(ctx, req)
 
+		auditEvent := entity.AuditEventReq{Action: entity.AuditActionLogin, Metadata: map[string]string{"username": req.Username}, Err: err}
+		if err == nil {
+			auditEvent.ActorId, auditEvent.TargetMemberId = res.Member.ID, res.Member.ID
+			if res.TwoFactorRequired {
+				auditEvent.Metadata["two_factor"] = "required"
+			}
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
 		if err != nil {
 			log.Error(ctx, err.Error())
+			if r.sendLoginLockedError(c, err, traceID) {
+				return
+			}
 			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
 			return
 		}
 
-		token, err := r.CreateMemberToken(*res)
+		if res.TwoFactorRequired {
+			challenge, err := r.CreateTwoFactorChallengeToken(res.Member, req.DeviceId, r.twoFactorConfig().ChallengeLifetime)
+			if err != nil {
+				log.Error(ctx, err.Error())
+				r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+				return
+			}
+
+			r.Helper.SendSuccess(c, "Success", challenge, traceID)
+			return
+		}
+
+		finalResponse, err := r.createMemberSession(ctx, sessionInputPort, res.Member, entity.CreateSessionReq{
+			DeviceId:  req.DeviceId,
+			Platform:  req.Platform,
+			UserAgent: req.UserAgent,
+			IpAddress: req.IpAddress,
+			Amr:       []string{entity.AuthMethodPassword},
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.sendMemberAuth(c, *finalResponse, traceID)
+	}
+}
+
+func ApiBaseRefreshAuthMember(r *Controller) gin.HandlerFunc {
+	var inputPort = refreshauthmemberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		req := entity.AuthRefreshToken{
+			Id:       claims.Subject,
+			DeviceId: claims.DeviceId,
+			TokenId:  claims.ID,
+			FamilyId: claims.SessionId,
+		}
+
+		res, err := inputPort.Execute(ctx, entity.RefreshAuthReq{
+			Token:     req,
+			ExpiredAt: r.refreshTokenExpiredAt(),
+		})
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionRefresh,
+			TargetMemberId: claims.Subject,
+			Metadata:       map[string]string{"session_id": claims.SessionId},
+			Err:            err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendUnauthorizedError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+			return
+		}
+
+		token, err := r.CreateMemberToken(res.Member, res.Session)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+		refreshToken, err := r.CreateMemberRefreshToken(res.RefreshToken.ToAuthRefreshToken(), res.Member.CredentialVersion)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		finalResponse := res.Member.ToResAuth(token, refreshToken)
+
+		r.sendMemberAuth(c, finalResponse, traceID)
+	}
+}
+
+func ApiBaseAppLogoutMember(r *Controller) gin.HandlerFunc {
+	var inputPort = logoutmemberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		// an impersonation token share the session of the admin, only the token ends
+		familyId := claims.SessionId
+		if claims.IsImpersonated() {
+			familyId = ""
+		}
+
+		err = inputPort.Execute(ctx, entity.LogoutReq{
+			MemberId:  claims.Subject,
+			TokenId:   claims.ID,
+			FamilyId:  familyId,
+			ExpiredAt: claims.GetExpiresAt(),
+		})
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionLogout,
+			TargetMemberId: claims.Subject,
+			Metadata:       map[string]string{"session_id": familyId},
+			Err:            err,
+		})
+
 		if err != nil {
 			log.Error(ctx, err.Error())
 			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
 			return
 		}
-		refreshToken, err := r.CreateMemberRefreshToken(entity.AuthRefreshToken{
-			Id:       res.ID,
-			DeviceId: res.DeviceId,
+
+		r.clearSessionCookies(c)
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
+
+func ApiBaseAppLogoutAllMember(r *Controller) gin.HandlerFunc {
+	var inputPort = logoutallmemberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		err = inputPort.Execute(ctx, entity.LogoutAllReq{
+			MemberId:  claims.Subject,
+			ExpiredAt: r.revokeMemberExpiredAt(),
 		})
 
-		fmt.Println("TAG LOGIN RESPONSE => ", res)
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionLogoutAll,
+			TargetMemberId: claims.Subject,
+			Err:            err,
+		})
 
 		if err != nil {
 			log.Error(ctx, err.Error())
@@ -57,50 +237,113 @@ func ApiBaseAppAuthMember(r *Controller) gin.HandlerFunc {
 			return
 		}
 
-		finalResponse := entity.MemberResAuth{
-			ID:             res.ID,
-			Username:       res.Username,
-			Fullname:       res.Fullname,
-			MemberType:     res.MemberType,
-			IsSuspend:      res.IsSuspend,
-			CreatedAt:      res.CreatedAt,
-			UpdatedAt:      res.UpdatedAt,
-			LastLogin:      res.LastLogin,
-			TokenBroadcast: res.TokenBroadcast,
-			DeviceId:       res.DeviceId,
-			PhoneNumber:    res.PhoneNumber,
-			Email:          res.Email,
-			MemberPhoto:    res.MemberPhoto,
-			Token:          token,
-			RefreshToken:   refreshToken,
+		r.clearSessionCookies(c)
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
+
+func ApiBaseAppSessionFindAll(r *Controller) gin.HandlerFunc {
+	var inputPort = getallsessionv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, claims.Subject)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		finalResponse := make([]entity.SessionDataShown, 0, len(res))
+		for _, session := range res {
+			finalResponse = append(finalResponse, entity.SessionDataShown{
+				SessionData: session,
+				IsCurrent:   session.ID == claims.SessionId,
+			})
 		}
 
 		r.Helper.SendSuccess(c, "Success", finalResponse, traceID)
 	}
 }
 
-func ApiBaseRefreshAuthMember(r *Controller) gin.HandlerFunc {
-	var inputPort = getmemberv1.NewUsecase(r.DataSource)
+func ApiBaseAppSessionRevoke(r *Controller) gin.HandlerFunc {
+	var inputPort = revokesessionv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
 
 	return func(c *gin.Context) {
 		traceID := util.GenerateID()
 		ctx := log.Context(c.Request.Context(), traceID)
-		req := entity.AuthRefreshToken{}
 
-		var err error
 		//get claim from JWT token
-		claim, err := r.Helper.GetJsonClaimFromContext(c)
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
 		if err != nil {
 			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
 			return
 		}
 
-		err = json.Unmarshal([]byte(claim), &req)
+		err = inputPort.Execute(ctx, entity.RevokeSessionReq{
+			MemberId:  claims.Subject,
+			SessionId: c.Param("id"),
+		})
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionSessionRevoke,
+			TargetMemberId: claims.Subject,
+			Metadata:       map[string]string{"session_id": c.Param("id")},
+			Err:            err,
+		})
+
 		if err != nil {
-			fmt.Sprintf("error unmarshal user token : %s", err.Error())
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
+
+func ApiBaseAppForgotPassword(r *Controller) gin.HandlerFunc {
+	var inputPort = forgotpasswordv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.ForgotPasswordReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
 		}
 
-		res, err := inputPort.Execute(ctx, req.Id)
+		req.ResetUrl = r.Config.GetString("password_reset.url")
+		req.ExpiredAt = time.Now().Add(time.Duration(r.Config.GetInt("password_reset.token_minute")) * time.Minute)
+
+		err := inputPort.Execute(ctx, req)
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:   entity.AuditActionPasswordForgot,
+			Metadata: map[string]string{"username": req.Username, "channel": req.Channel},
+			Err:      err,
+		})
 
 		if err != nil {
 			log.Error(ctx, err.Error())
@@ -108,37 +351,215 @@ func ApiBaseRefreshAuthMember(r *Controller) gin.HandlerFunc {
 			return
 		}
 
-		token, err := r.CreateMemberToken(res)
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
+
+func ApiBaseAppResetPassword(r *Controller) gin.HandlerFunc {
+	var inputPort = resetpasswordv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.ResetPasswordReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.RevokeExpiredAt = r.revokeMemberExpiredAt()
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{Action: entity.AuditActionPasswordReset, Err: err}
+		if err == nil {
+			auditEvent.ActorId, auditEvent.TargetMemberId = res.ID, res.ID
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
 		if err != nil {
 			log.Error(ctx, err.Error())
+			if r.sendPasswordPolicyError(c, err, traceID) {
+				return
+			}
 			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
 			return
 		}
-		refreshToken, err := r.CreateMemberRefreshToken(entity.AuthRefreshToken{
-			Id:       res.ID,
-			DeviceId: res.DeviceId,
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
+
+// ApiBaseAppChangePassword reject every token issued before the change. With keep_current_session
+// the other sessions are logged out and the current one get new tokens, otherwise every session is logged out
+func ApiBaseAppChangePassword(r *Controller) gin.HandlerFunc {
+	var inputPort = changepasswordv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		var req entity.ChangePasswordReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.MemberId = claims.Subject
+		req.SessionId = claims.SessionId
+		req.IpAddress = c.ClientIP()
+		req.LoginProtection = r.loginProtectionConfig()
+		req.RefreshTokenExpiredAt = r.refreshTokenExpiredAt()
+
+		res, err := inputPort.Execute(ctx, req)
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionPasswordChange,
+			TargetMemberId: claims.Subject,
+			Metadata:       map[string]string{"keep_current_session": strconv.FormatBool(req.KeepCurrentSession)},
+			Err:            err,
 		})
 
-		finalResponse := entity.MemberResAuth{
-			ID:             res.ID,
-			Username:       res.Username,
-			Fullname:       res.Fullname,
-			MemberType:     res.MemberType,
-			IsSuspend:      res.IsSuspend,
-			CreatedAt:      res.CreatedAt,
-			UpdatedAt:      res.UpdatedAt,
-			LastLogin:      res.LastLogin,
-			TokenBroadcast: res.TokenBroadcast,
-			DeviceId:       res.DeviceId,
-			PhoneNumber:    res.PhoneNumber,
-			Email:          res.Email,
-			MemberPhoto:    res.MemberPhoto,
-			Token:          token,
-			RefreshToken:   refreshToken,
+		if err != nil {
+			log.Error(ctx, err.Error())
+			if r.sendLoginLockedError(c, err, traceID) || r.sendPasswordPolicyError(c, err, traceID) {
+				return
+			}
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		if res.Session == nil {
+			r.clearSessionCookies(c)
+			r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+			return
 		}
 
-		r.Helper.SendSuccess(c, "Success", finalResponse, traceID)
+		token, err := r.CreateMemberToken(res.Member, *res.Session)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+		refreshToken, err := r.CreateMemberRefreshToken(res.RefreshToken.ToAuthRefreshToken(), res.Member.CredentialVersion)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.sendMemberAuth(c, res.Member.ToResAuth(token, refreshToken), traceID)
+	}
+}
+
+func ApiBaseAppRequestVerification(r *Controller) gin.HandlerFunc {
+	var inputPort = requestverificationv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.RequestVerificationReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.Config = r.verificationConfig()
 
-		return
+		err := inputPort.Execute(ctx, req)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
+
+func ApiBaseAppConfirmVerification(r *Controller) gin.HandlerFunc {
+	var inputPort = confirmverificationv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.ConfirmVerificationReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.Config = r.verificationConfig()
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{
+			Action:   entity.AuditActionVerificationConfirm,
+			Metadata: map[string]string{"username": req.Username, "channel": req.Channel},
+			Err:      err,
+		}
+		if err == nil {
+			auditEvent.ActorId, auditEvent.TargetMemberId = res.ID, res.ID
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
+
+// ApiBaseAppJwks publish the public keys in the standard JWKS format, without the response wrapper,
+// so other services can verify member tokens
+func ApiBaseAppJwks(r *Controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, r.KeySet.JWKS())
 	}
 }
@@ -0,0 +1,144 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/member/v1/deletememberv1"
+	"backend_base_app/usecase/member/v1/purgememberv1"
+	"backend_base_app/usecase/member/v1/restorememberv1"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiBaseAppMemberDelete soft delete a member, a member deleting itself need a recent authentication
+func ApiBaseAppMemberDelete(r *Controller) gin.HandlerFunc {
+	var inputPort = deletememberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		req := entity.DeleteMemberReq{
+			MemberId:           c.Param("id"),
+			DeletedBy:          claims.Subject,
+			ExpiredAt:          r.revokeMemberExpiredAt(),
+			GrantedPermissions: r.getGrantedPermissionsFromContext(c),
+		}
+		if req.MemberId == claims.Subject {
+			req.AuthTime = claims.GetAuthTime()
+			req.StepUpMaxAge = r.stepUpMaxAge()
+		}
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{
+			Action:         entity.AuditActionMemberDelete,
+			TargetMemberId: req.MemberId,
+			Err:            err,
+		}
+		if err == nil {
+			auditEvent.Changes = entity.NewAuditChanges(res.Previous.Deletion(), res.Member.Deletion())
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			if errors.Is(err, entity.ReauthenticationRequired) {
+				r.Helper.SendUnauthorizedError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+				return
+			}
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		// a member deleting itself is logged out
+		if req.MemberId == claims.Subject {
+			r.clearSessionCookies(c)
+		}
+
+		r.Helper.SendSuccess(c, "Success", res.Member, traceID)
+	}
+}
+
+func ApiBaseAppMemberRestore(r *Controller) gin.HandlerFunc {
+	var inputPort = restorememberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		memberId := c.Param("id")
+
+		res, err := inputPort.Execute(ctx, entity.RestoreMemberReq{
+			MemberId:           memberId,
+			GrantedPermissions: r.getGrantedPermissionsFromContext(c),
+		})
+
+		auditEvent := entity.AuditEventReq{
+			Action:         entity.AuditActionMemberRestore,
+			TargetMemberId: memberId,
+			Err:            err,
+		}
+		if err == nil {
+			auditEvent.Changes = entity.NewAuditChanges(res.Previous.Deletion(), res.Member.Deletion())
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res.Member, traceID)
+	}
+}
+
+// ApiBaseAppMemberPurge remove a soft deleted member for good, the audit log keep its username
+func ApiBaseAppMemberPurge(r *Controller) gin.HandlerFunc {
+	var inputPort = purgememberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		memberId := c.Param("id")
+
+		res, err := inputPort.Execute(ctx, entity.PurgeMemberReq{
+			MemberId:           memberId,
+			GrantedPermissions: r.getGrantedPermissionsFromContext(c),
+		})
+
+		auditEvent := entity.AuditEventReq{
+			Action:         entity.AuditActionMemberPurge,
+			TargetMemberId: memberId,
+			Err:            err,
+		}
+		if err == nil {
+			auditEvent.Metadata = map[string]string{"username": res.Username}
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
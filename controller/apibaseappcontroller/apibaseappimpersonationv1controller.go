@@ -0,0 +1,90 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/member/v1/impersonatememberv1"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ApiBaseAppMemberImpersonate(r *Controller) gin.HandlerFunc {
+	var inputPort = impersonatememberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+		actor, err := r.getMemberFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		var req entity.ImpersonateMemberReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		req.MemberId = c.Param("id")
+		req.ActorId = actor.ID
+		req.ActorRoles = actor.GetRoles()
+
+		if err := req.Validate(); err != nil {
+			if errors.Is(err, entity.ImpersonationRequireSuperadmin) {
+				// an attempt without the role is kept in the audit log
+				r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+					Action:         entity.AuditActionImpersonate,
+					TargetMemberId: req.MemberId,
+					Metadata:       map[string]string{"reason": req.Reason},
+					Err:            err,
+				})
+				r.Helper.SendForbiddenError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+				return
+			}
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		res := &entity.ImpersonateMemberRes{ImpersonatedBy: actor.ID}
+		member, err := inputPort.Execute(ctx, req)
+		if err == nil {
+			res.Member = *member
+			res.Token, res.ExpiredAt, err = r.CreateImpersonationToken(*member, actor.ID, claims.SessionId)
+		}
+
+		auditEvent := entity.AuditEventReq{
+			Action:         entity.AuditActionImpersonate,
+			TargetMemberId: req.MemberId,
+			Metadata:       map[string]string{"reason": req.Reason},
+			Err:            err,
+		}
+		if err == nil {
+			auditEvent.Metadata["expired_at"] = res.ExpiredAt.UTC().Format(time.RFC3339)
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
@@ -0,0 +1,114 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/lib/core/spreadsheet"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/member/v1/importmemberv1"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiBaseAppMemberImport create the members of the csv or xlsx file field of a multipart form,
+// ?dry_run=true only validate the rows. The answer report every row
+func ApiBaseAppMemberImport(r *Controller) gin.HandlerFunc {
+	var inputPort = importmemberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		config := r.memberImportConfig()
+		dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+		// the form around the file is small, a larger body is not read
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MaxSize+64*1024)
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				r.Helper.SendBadRequest(c, entity.MemberImportFileTooLarge.Error(), nil, traceID)
+				return
+			}
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		if fileHeader.Size > config.MaxSize {
+			r.Helper.SendBadRequest(c, entity.MemberImportFileTooLarge.Error(), nil, traceID)
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, config.MaxSize))
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		rows, err := entity.ReadMemberImportRows(spreadsheet.DetectFormat(fileHeader.Filename, data), data, config)
+		if err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, entity.ImportMemberReq{
+			Rows:      rows,
+			DryRun:    dryRun,
+			BatchSize: config.BatchSize,
+		})
+
+		auditEvent := entity.AuditEventReq{
+			Action:   entity.AuditActionMemberImport,
+			Metadata: map[string]string{"file_name": fileHeader.Filename, "dry_run": strconv.FormatBool(dryRun)},
+			Err:      err,
+		}
+		if err == nil {
+			auditEvent.Metadata["created"] = strconv.Itoa(res.Created)
+			auditEvent.Metadata["skipped"] = strconv.Itoa(res.Skipped)
+			auditEvent.Metadata["failed"] = strconv.Itoa(res.Failed)
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		// every created member is in the audit log, like a member created one at a time
+		if !res.DryRun {
+			for _, row := range res.Rows {
+				if row.Status != entity.MemberImportCreated {
+					continue
+				}
+				r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+					Action:         entity.AuditActionMemberCreate,
+					TargetMemberId: row.MemberId,
+					Metadata:       map[string]string{"username": row.Username, "source": "import"},
+				})
+			}
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
@@ -0,0 +1,82 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/loginattempt/v1/deleteloginattemptv1"
+	"backend_base_app/usecase/loginattempt/v1/getallloginattemptv1"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ApiBaseAppLockoutFindAll(r *Controller) gin.HandlerFunc {
+	var inputPort = getallloginattemptv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.BaseReqFind
+		if err := c.BindQuery(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		var reqValue entity.LoginAttemptDataFind
+		c.BindQuery(&reqValue)
+		req.Value = reqValue
+
+		sortByParams := make(map[string]interface{})
+		for key, value := range c.Request.URL.Query() {
+			if strings.HasPrefix(key, "sort_by_") {
+				trimmedKey := strings.TrimPrefix(key, "sort_by_")
+				sortByParams[trimmedKey] = value
+			}
+		}
+		req.SortBy = sortByParams
+
+		res, count, err := inputPort.Execute(ctx, req)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		finalResponse := req.ToResponse(res, count)
+
+		r.Helper.SendSuccess(c, "Success", finalResponse, traceID)
+	}
+}
+
+func ApiBaseAppLockoutDelete(r *Controller) gin.HandlerFunc {
+	var inputPort = deleteloginattemptv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		err := inputPort.Execute(ctx, c.Param("id"))
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:   entity.AuditActionLockoutDelete,
+			Metadata: map[string]string{"login_attempt_id": c.Param("id")},
+			Err:      err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
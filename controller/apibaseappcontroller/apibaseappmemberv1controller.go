@@ -5,10 +5,17 @@ import (
 	"backend_base_app/domain/entity"
 	"backend_base_app/shared/log"
 	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
 	"backend_base_app/usecase/member/v1/creatememberv1"
 	"backend_base_app/usecase/member/v1/getallmemberv1"
 	"backend_base_app/usecase/member/v1/getmemberv1"
+	"backend_base_app/usecase/member/v1/updatememberv1"
+	"backend_base_app/usecase/member/v1/uploadmemberphotov1"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -16,6 +23,7 @@ import (
 
 func ApiBaseAppMemberCreate(r *Controller) gin.HandlerFunc {
 	var inputPort = creatememberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
 
 	return func(c *gin.Context) {
 		traceID := util.GenerateID()
@@ -36,8 +44,21 @@ func ApiBaseAppMemberCreate(r *Controller) gin.HandlerFunc {
 
 		res, err := inputPort.Execute(ctx, req)
 
+		auditEvent := entity.AuditEventReq{
+			Action:   entity.AuditActionMemberCreate,
+			Metadata: map[string]string{"username": req.Username, "member_type": req.MemberType},
+			Err:      err,
+		}
+		if err == nil {
+			auditEvent.TargetMemberId = res.ID
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
 		if err != nil {
 			log.Error(ctx, err.Error())
+			if r.sendPasswordPolicyError(c, err, traceID) {
+				return
+			}
 			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
 			return
 		}
@@ -115,3 +136,145 @@ func ApiBaseAppMemberFindOne(r *Controller) gin.HandlerFunc {
 		r.Helper.SendSuccess(c, "Success", res, traceID)
 	}
 }
+
+// ApiBaseAppMemberUpdate only change the fields sent in the body. A member updating itself
+// need a recent authentication to change its email or phone number
+func ApiBaseAppMemberUpdate(r *Controller) gin.HandlerFunc {
+	var inputPort = updatememberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		var req entity.UpdateMemberReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		req.MemberId = c.Param("id")
+		req.UpdatedBy = claims.Subject
+		req.GrantedPermissions = r.getGrantedPermissionsFromContext(c)
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		if req.MemberId == claims.Subject {
+			req.AuthTime = claims.GetAuthTime()
+			req.StepUpMaxAge = r.stepUpMaxAge()
+		}
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{
+			Action:         entity.AuditActionMemberUpdate,
+			TargetMemberId: req.MemberId,
+			Err:            err,
+		}
+		if err == nil {
+			auditEvent.Changes = entity.NewAuditChanges(res.Previous.Profile(), res.Member.Profile())
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			if errors.Is(err, entity.ReauthenticationRequired) {
+				r.Helper.SendUnauthorizedError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+				return
+			}
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res.Member, traceID)
+	}
+}
+
+// ApiBaseAppMemberPhotoUpload read the photo field of a multipart form, the type is sniffed from the content
+func ApiBaseAppMemberPhotoUpload(r *Controller) gin.HandlerFunc {
+	var inputPort = uploadmemberphotov1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		req := entity.UploadMemberPhotoReq{
+			MemberId: c.Param("id"),
+			Config:   r.memberPhotoConfig(ctx),
+		}
+
+		// the form around the file is small, a larger body is not read
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, req.Config.MaxSize+64*1024)
+
+		fileHeader, err := c.FormFile("photo")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				r.Helper.SendBadRequest(c, entity.MemberPhotoTooLarge.Error(), nil, traceID)
+				return
+			}
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		if fileHeader.Size > req.Config.MaxSize {
+			r.Helper.SendBadRequest(c, entity.MemberPhotoTooLarge.Error(), nil, traceID)
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+		defer file.Close()
+
+		req.Data, err = io.ReadAll(io.LimitReader(file, req.Config.MaxSize+1))
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+		req.ContentType = http.DetectContentType(req.Data)
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{
+			Action:         entity.AuditActionMemberPhotoUpload,
+			TargetMemberId: req.MemberId,
+			Metadata:       map[string]string{"content_type": req.ContentType, "size": strconv.Itoa(len(req.Data))},
+			Err:            err,
+		}
+		if err == nil {
+			auditEvent.Changes = entity.NewAuditChanges(res.Previous.Photo(), res.Member.Photo())
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res.Member, traceID)
+	}
+}
@@ -0,0 +1,119 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/authorization/v1/createsessionv1"
+	"backend_base_app/usecase/oidc/v1/callbackoidcv1"
+	"backend_base_app/usecase/oidc/v1/startoidcv1"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiBaseAppOidcStart return the authorization url of the provider, the client open it in a browser
+func ApiBaseAppOidcStart(r *Controller) gin.HandlerFunc {
+	var inputPort = startoidcv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.OidcStartReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		req.Provider = c.Param("provider")
+		req.Config = r.oidcLoginConfig()
+
+		res, err := inputPort.Execute(ctx, req)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
+
+// ApiBaseAppOidcCallback is the redirect url registered at the provider, it answer like the login
+func ApiBaseAppOidcCallback(r *Controller) gin.HandlerFunc {
+	var inputPort = callbackoidcv1.NewUsecase(r.DataSource)
+	var sessionInputPort = createsessionv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.OidcCallbackReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		req.Provider = c.Param("provider")
+		req.Config = r.oidcLoginConfig()
+
+		if err := req.Validate(); err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{Action: entity.AuditActionLoginOidc, Metadata: map[string]string{"provider": req.Provider}, Err: err}
+		if err == nil {
+			auditEvent.ActorId, auditEvent.TargetMemberId = res.Member.ID, res.Member.ID
+			if res.TwoFactorRequired {
+				auditEvent.Metadata["two_factor"] = "required"
+			}
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		if res.TwoFactorRequired {
+			challenge, err := r.CreateTwoFactorChallengeToken(res.Member, res.DeviceId, r.twoFactorConfig().ChallengeLifetime)
+			if err != nil {
+				log.Error(ctx, err.Error())
+				r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+				return
+			}
+
+			r.Helper.SendSuccess(c, "Success", challenge, traceID)
+			return
+		}
+
+		finalResponse, err := r.createMemberSession(ctx, sessionInputPort, res.Member, entity.CreateSessionReq{
+			DeviceId:  res.DeviceId,
+			Platform:  res.Platform,
+			UserAgent: c.Request.UserAgent(),
+			IpAddress: c.ClientIP(),
+			Amr:       []string{entity.AuthMethodFederated},
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.sendMemberAuth(c, *finalResponse, traceID)
+	}
+}
@@ -0,0 +1,130 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/authorization/v1/createsessionv1"
+	"backend_base_app/usecase/passwordless/v1/completepasswordlessv1"
+	"backend_base_app/usecase/passwordless/v1/startpasswordlessv1"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ApiBaseAppPasswordlessStart(r *Controller) gin.HandlerFunc {
+	var inputPort = startpasswordlessv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.StartPasswordlessReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.Config = r.passwordlessConfig(ctx)
+
+		err := inputPort.Execute(ctx, req)
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:   entity.AuditActionPasswordlessStart,
+			Metadata: map[string]string{"username": req.Username, "channel": req.Channel},
+			Err:      err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
+
+func ApiBaseAppPasswordlessComplete(r *Controller) gin.HandlerFunc {
+	var inputPort = completepasswordlessv1.NewUsecase(r.DataSource)
+	var sessionInputPort = createsessionv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.CompletePasswordlessReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.UserAgent = c.Request.UserAgent()
+		req.IpAddress = c.ClientIP()
+		req.RequiredVerification = r.requiredVerification(ctx)
+		req.Config = r.passwordlessConfig(ctx)
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{Action: entity.AuditActionLoginPasswordless, Metadata: map[string]string{"username": req.Username}, Err: err}
+		if err == nil {
+			auditEvent.ActorId, auditEvent.TargetMemberId = res.Member.ID, res.Member.ID
+			if res.TwoFactorRequired {
+				auditEvent.Metadata["two_factor"] = "required"
+			}
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		if res.TwoFactorRequired {
+			challenge, err := r.CreateTwoFactorChallengeToken(res.Member, req.DeviceId, r.twoFactorConfig().ChallengeLifetime)
+			if err != nil {
+				log.Error(ctx, err.Error())
+				r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+				return
+			}
+
+			r.Helper.SendSuccess(c, "Success", challenge, traceID)
+			return
+		}
+
+		finalResponse, err := r.createMemberSession(ctx, sessionInputPort, res.Member, entity.CreateSessionReq{
+			DeviceId:  req.DeviceId,
+			Platform:  req.Platform,
+			UserAgent: req.UserAgent,
+			IpAddress: req.IpAddress,
+			Amr:       []string{req.AuthMethod()},
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.sendMemberAuth(c, *finalResponse, traceID)
+	}
+}
@@ -0,0 +1,78 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/authorization/v1/reauthenticatememberv1"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiBaseAppReauthenticate check the password or a second factor code of the logged in member again,
+// it answer an access token with a new auth_time for the step-up routes. The refresh token is unchanged
+func ApiBaseAppReauthenticate(r *Controller) gin.HandlerFunc {
+	var inputPort = reauthenticatememberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		var req entity.ReauthenticateReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.MemberId = claims.Subject
+		req.SessionId = claims.SessionId
+		req.IpAddress = c.ClientIP()
+		req.LoginProtection = r.loginProtectionConfig()
+		req.Config = r.twoFactorConfig()
+
+		res, err := inputPort.Execute(ctx, req)
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionReauthenticate,
+			TargetMemberId: claims.Subject,
+			Metadata:       map[string]string{"session_id": claims.SessionId, "amr": strings.Join(req.AuthMethods(), " ")},
+			Err:            err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			if r.sendLoginLockedError(c, err, traceID) {
+				return
+			}
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		token, err := r.CreateMemberToken(res.Member, res.Session)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.sendMemberAuth(c, res.Member.ToResAuth(token, ""), traceID)
+	}
+}
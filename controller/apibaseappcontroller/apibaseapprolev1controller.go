@@ -0,0 +1,222 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/member/v1/assignmemberrolev1"
+	"backend_base_app/usecase/role/v1/createrolev1"
+	"backend_base_app/usecase/role/v1/deleterolev1"
+	"backend_base_app/usecase/role/v1/getallpermissionv1"
+	"backend_base_app/usecase/role/v1/getallrolev1"
+	"backend_base_app/usecase/role/v1/updaterolev1"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ApiBaseAppRoleCreate(r *Controller) gin.HandlerFunc {
+	var inputPort = createrolev1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.CreateRoleData
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		req.GrantedPermissions = r.getGrantedPermissionsFromContext(c)
+
+		if err := req.ValidateCreate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{Action: entity.AuditActionRoleCreate, Metadata: map[string]string{"name": req.Name}, Err: err}
+		if err == nil {
+			auditEvent.Metadata["role_id"] = res.ID
+			auditEvent.Changes = entity.NewAuditChanges(nil, res)
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
+
+func ApiBaseAppRoleFindAll(r *Controller) gin.HandlerFunc {
+	var inputPort = getallrolev1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.BaseReqFind
+		if err := c.BindQuery(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		var reqValue entity.RoleDataFind
+		c.BindQuery(&reqValue)
+		req.Value = reqValue
+
+		sortByParams := make(map[string]interface{})
+		for key, value := range c.Request.URL.Query() {
+			if strings.HasPrefix(key, "sort_by_") {
+				trimmedKey := strings.TrimPrefix(key, "sort_by_")
+				sortByParams[trimmedKey] = value
+			}
+		}
+		req.SortBy = sortByParams
+
+		res, count, err := inputPort.Execute(ctx, req)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		finalResponse := req.ToResponse(res, count)
+
+		r.Helper.SendSuccess(c, "Success", finalResponse, traceID)
+	}
+}
+
+func ApiBaseAppRoleUpdate(r *Controller) gin.HandlerFunc {
+	var inputPort = updaterolev1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.UpdateRoleData
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		req.ID = c.Param("id")
+		req.GrantedPermissions = r.getGrantedPermissionsFromContext(c)
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{Action: entity.AuditActionRoleUpdate, Metadata: map[string]string{"role_id": req.ID}, Err: err}
+		if err == nil {
+			auditEvent.Changes = entity.NewAuditChanges(res.Previous, res.Role)
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res.Role, traceID)
+	}
+}
+
+func ApiBaseAppRoleDelete(r *Controller) gin.HandlerFunc {
+	var inputPort = deleterolev1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		err := inputPort.Execute(ctx, entity.DeleteRoleReq{
+			ID:                 c.Param("id"),
+			GrantedPermissions: r.getGrantedPermissionsFromContext(c),
+		})
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:   entity.AuditActionRoleDelete,
+			Metadata: map[string]string{"role_id": c.Param("id")},
+			Err:      err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
+
+func ApiBaseAppPermissionFindAll(r *Controller) gin.HandlerFunc {
+	var inputPort = getallpermissionv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		res, err := inputPort.Execute(ctx)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
+
+func ApiBaseAppMemberAssignRole(r *Controller) gin.HandlerFunc {
+	var inputPort = assignmemberrolev1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.AssignMemberRoleReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		req.MemberId = c.Param("id")
+		req.GrantedPermissions = r.getGrantedPermissionsFromContext(c)
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{Action: entity.AuditActionMemberRoleAssign, TargetMemberId: req.MemberId, Err: err}
+		if err == nil {
+			auditEvent.Changes = entity.NewAuditChanges(map[string][]string{"roles": res.PreviousRoles}, map[string][]string{"roles": res.Member.Roles})
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res.Member, traceID)
+	}
+}
@@ -0,0 +1,114 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/member/v1/suspendmemberv1"
+	"backend_base_app/usecase/member/v1/unsuspendmemberv1"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ApiBaseAppMemberSuspend(r *Controller) gin.HandlerFunc {
+	var inputPort = suspendmemberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		//get claim from JWT token
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		var req entity.SuspendMemberReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		req.MemberId = c.Param("id")
+		req.SuspendedBy = claims.Subject
+		req.ExpiredAt = r.revokeMemberExpiredAt()
+		req.GrantedPermissions = r.getGrantedPermissionsFromContext(c)
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{
+			Action:         entity.AuditActionMemberSuspend,
+			TargetMemberId: req.MemberId,
+			Metadata:       map[string]string{"reason": req.Reason},
+			Err:            err,
+		}
+		if err == nil {
+			auditEvent.Changes = entity.NewAuditChanges(res.Previous.Suspension(), res.Member.Suspension())
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res.Member, traceID)
+	}
+}
+
+func ApiBaseAppMemberUnsuspend(r *Controller) gin.HandlerFunc {
+	var inputPort = unsuspendmemberv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.UnsuspendMemberReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+		req.MemberId = c.Param("id")
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, req)
+
+		auditEvent := entity.AuditEventReq{
+			Action:         entity.AuditActionMemberUnsuspend,
+			TargetMemberId: req.MemberId,
+			Metadata:       map[string]string{"reason": req.Reason},
+			Err:            err,
+		}
+		if err == nil {
+			auditEvent.Changes = entity.NewAuditChanges(res.Previous.Suspension(), res.Member.Suspension())
+		}
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, auditEvent)
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res.Member, traceID)
+	}
+}
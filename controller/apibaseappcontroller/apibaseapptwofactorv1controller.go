@@ -0,0 +1,220 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/helper"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/authorization/v1/authtwofactorv1"
+	"backend_base_app/usecase/authorization/v1/createsessionv1"
+	"backend_base_app/usecase/twofactor/v1/confirmtwofactorv1"
+	"backend_base_app/usecase/twofactor/v1/disabletwofactorv1"
+	"backend_base_app/usecase/twofactor/v1/enrolltwofactorv1"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiBaseAppAuthTwoFactor exchange the challenge token returned by the login and a totp or recovery code
+// for the member tokens
+func ApiBaseAppAuthTwoFactor(r *Controller) gin.HandlerFunc {
+	var inputPort = authtwofactorv1.NewUsecase(r.DataSource)
+	var sessionInputPort = createsessionv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		var req entity.TwoFactorLoginReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		claims, err := r.parseMemberToken(r.RefreshKeySet, helper.TokenTypeTwoFactorChallenge, req.ChallengeToken)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendUnauthorizedError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+			return
+		}
+
+		req.MemberId = claims.Subject
+		req.ChallengeId = claims.ID
+		req.ChallengeIssuedAt = claims.GetIssuedAt()
+		req.ChallengeExpiredAt = claims.GetExpiresAt()
+		req.DeviceId = claims.DeviceId
+		req.UserAgent = c.Request.UserAgent()
+		req.IpAddress = c.ClientIP()
+		req.LoginProtection = r.loginProtectionConfig()
+		req.Config = r.twoFactorConfig()
+
+		res, err := inputPort.Execute(ctx, req)
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionLoginTwoFactor,
+			ActorId:        req.MemberId,
+			TargetMemberId: req.MemberId,
+			Err:            err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			if r.sendLoginLockedError(c, err, traceID) {
+				return
+			}
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		finalResponse, err := r.createMemberSession(ctx, sessionInputPort, *res, entity.CreateSessionReq{
+			DeviceId:  req.DeviceId,
+			Platform:  req.Platform,
+			UserAgent: req.UserAgent,
+			IpAddress: req.IpAddress,
+			Amr:       []string{entity.AuthMethodPassword, entity.AuthMethodOtp, entity.AuthMethodMfa},
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.sendMemberAuth(c, *finalResponse, traceID)
+	}
+}
+
+func ApiBaseAppTwoFactorEnroll(r *Controller) gin.HandlerFunc {
+	var inputPort = enrolltwofactorv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		member, err := r.getMemberFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		res, err := inputPort.Execute(ctx, entity.EnrollTwoFactorReq{
+			MemberId:    member.ID,
+			AccountName: member.Username,
+			Config:      r.twoFactorConfig(),
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
+
+func ApiBaseAppTwoFactorConfirm(r *Controller) gin.HandlerFunc {
+	var inputPort = confirmtwofactorv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		var req entity.ConfirmTwoFactorReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.MemberId = claims.Subject
+		req.Config = r.twoFactorConfig()
+
+		res, err := inputPort.Execute(ctx, req)
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionTwoFactorEnable,
+			TargetMemberId: claims.Subject,
+			Err:            err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", res, traceID)
+	}
+}
+
+func ApiBaseAppTwoFactorDisable(r *Controller) gin.HandlerFunc {
+	var inputPort = disabletwofactorv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	return func(c *gin.Context) {
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err != nil {
+			r.Helper.SendUnauthorizedError(c, err.Error(), err.Error(), traceID)
+			return
+		}
+
+		var req entity.DisableTwoFactorReq
+		if err := c.Bind(&req); err != nil {
+			newErr := domerror.FailUnmarshalRequestBodyError
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), newErr, traceID)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			r.Helper.SendBadRequest(c, err.Error(), nil, traceID)
+			return
+		}
+
+		req.MemberId = claims.Subject
+		req.Config = r.twoFactorConfig()
+
+		err = inputPort.Execute(ctx, req)
+
+		r.recordAuditEvent(ctx, c, auditInputPort, traceID, entity.AuditEventReq{
+			Action:         entity.AuditActionTwoFactorDisable,
+			TargetMemberId: claims.Subject,
+			Err:            err,
+		})
+
+		if err != nil {
+			log.Error(ctx, err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.Helper.SendSuccess(c, "Success", r.Helper.EmptyJsonMap(), traceID)
+	}
+}
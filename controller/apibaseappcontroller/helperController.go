@@ -2,32 +2,411 @@ package apibaseappcontroller
 
 import (
 	"backend_base_app/domain/entity"
+	"backend_base_app/lib/core/jwtkey"
+	appMiddleware "backend_base_app/lib/wrapper/middleware"
+	"backend_base_app/shared/helper"
+	"backend_base_app/shared/log"
 	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/authorization/v1/createsessionv1"
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
 )
 
+// CreateMemberToken carry the last authentication of the session, so a refreshed token keep it
 func (r Controller) CreateMemberToken(
 	data entity.MemberDataShown,
+	session entity.SessionData,
 ) (string, error) {
-	//marshal authorizationResult into json
-
-	authorizationResultJson := util.StructToJson(data)
-
-	fmt.Println("CREATE MEMBER TOKEN ", authorizationResultJson)
+	claims := helper.NewMemberClaims(helper.MemberClaimsReq{
+		TokenId:            util.GenerateUuidWithoutDash(),
+		MemberId:           data.ID,
+		DeviceId:           session.DeviceId,
+		SessionId:          session.ID,
+		Roles:              data.GetRoles(),
+		Type:               helper.TokenTypeAccess,
+		AuthTime:           session.AuthTime,
+		Amr:                session.Amr,
+		CredentialVersion:  data.CredentialVersion,
+		Issuer:             r.Config.GetString("api_app_base.token_issuer"),
+		Audience:           r.Config.GetString("api_app_base.token_audience"),
+		ConfidentialMinute: r.Config.GetInt("api_app_base.token_confidentiality_minute"),
+	})
 
-	//get confidentiality time
-	tokenConfidentiality := r.Config.GetInt("api_app_base.token_confidentiality_minute")
-	return r.Helper.CreateJwtToken(r.Config.GetString("api_app_base.secret"), string(authorizationResultJson), tokenConfidentiality)
+	return r.Helper.CreateJwtTokenWithKey(r.KeySet.SigningKey(), claims)
 }
 
 func (r Controller) CreateMemberRefreshToken(
 	data entity.AuthRefreshToken,
+	credentialVersion int,
 ) (string, error) {
-	//marshal authorizationResult into json
-	refreshTokenJson := util.StructToJson(data)
+	claims := helper.NewMemberClaims(helper.MemberClaimsReq{
+		TokenId:            data.TokenId,
+		MemberId:           data.Id,
+		DeviceId:           data.DeviceId,
+		SessionId:          data.FamilyId,
+		Type:               helper.TokenTypeRefresh,
+		CredentialVersion:  credentialVersion,
+		Issuer:             r.Config.GetString("api_app_base.token_issuer"),
+		Audience:           r.Config.GetString("api_app_base.token_audience"),
+		ConfidentialMinute: r.Config.GetInt("api_app_base.refresh_token_confidentiality_minute"),
+	})
+
+	return r.Helper.CreateJwtTokenWithKey(r.RefreshKeySet.SigningKey(), claims)
+}
+
+// CreateTwoFactorChallengeToken is signed with the refresh secret, it is only verified by this service
+func (r Controller) CreateTwoFactorChallengeToken(
+	data entity.MemberDataShown,
+	deviceId string,
+	lifetime time.Duration,
+) (*entity.TwoFactorChallengeRes, error) {
+	claims := helper.NewMemberClaims(helper.MemberClaimsReq{
+		TokenId:  util.GenerateUuidWithoutDash(),
+		MemberId: data.ID,
+		DeviceId: deviceId,
+		Type:     helper.TokenTypeTwoFactorChallenge,
+		Issuer:   r.Config.GetString("api_app_base.token_issuer"),
+		Audience: r.Config.GetString("api_app_base.token_audience"),
+	})
+	claims.ExpiresAt = jwt.NewNumericDate(claims.GetIssuedAt().Add(lifetime))
+
+	token, err := r.Helper.CreateJwtTokenWithKey(r.RefreshKeySet.SigningKey(), claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TwoFactorChallengeRes{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiredAt:         claims.GetExpiresAt(),
+	}, nil
+}
 
-	fmt.Println("CREATE MEMBER REFRESH TOKEN ", refreshTokenJson)
+// createMemberSession start a session and sign its tokens, it is the last step of every login
+func (r Controller) createMemberSession(
+	ctx context.Context,
+	inputPort createsessionv1.Inport,
+	member entity.MemberDataShown,
+	req entity.CreateSessionReq,
+) (*entity.MemberResAuth, error) {
+	req.MemberId = member.ID
+	req.MaxActive = r.maxActiveSession(member.MemberType)
+	req.ExpiredAt = r.refreshTokenExpiredAt()
+
+	sessionData, err := inputPort.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := r.CreateMemberToken(member, sessionData.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := r.CreateMemberRefreshToken(sessionData.RefreshToken.ToAuthRefreshToken(), member.CredentialVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	res := member.ToResAuth(token, refreshToken)
+
+	return &res, nil
+}
+
+// parseMemberToken validate the token signature, lifetime, type, issuer and audience
+func (r Controller) parseMemberToken(keySet *jwtkey.KeySet, tokenType, tokenString string) (*helper.MemberClaims, error) {
+	claims := &helper.MemberClaims{}
+	err := appMiddleware.ParseJwtTokenWithKeySet(keySet, tokenString, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	err = claims.VerifyToken(
+		tokenType,
+		r.Config.GetString("api_app_base.token_issuer"),
+		r.Config.GetString("api_app_base.token_audience"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
 
+// CreateImpersonationToken sign an access token of the member for the admin, it belong to the session
+// of the admin and can not be refreshed. It has no auth_time, so it never pass a step-up route
+func (r Controller) CreateImpersonationToken(
+	data entity.MemberDataShown,
+	actorId string,
+	sessionId string,
+) (string, time.Time, error) {
+	claims := helper.NewMemberClaims(helper.MemberClaimsReq{
+		TokenId:            util.GenerateUuidWithoutDash(),
+		MemberId:           data.ID,
+		SessionId:          sessionId,
+		Roles:              data.GetRoles(),
+		Type:               helper.TokenTypeAccess,
+		ActorId:            actorId,
+		CredentialVersion:  data.CredentialVersion,
+		Issuer:             r.Config.GetString("api_app_base.token_issuer"),
+		Audience:           r.Config.GetString("api_app_base.token_audience"),
+		ConfidentialMinute: r.impersonationTokenMinute(),
+	})
+
+	token, err := r.Helper.CreateJwtTokenWithKey(r.KeySet.SigningKey(), claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, claims.GetExpiresAt(), nil
+}
+
+func (r Controller) refreshTokenExpiredAt() time.Time {
 	refreshTokenConfidentiality := r.Config.GetInt("api_app_base.refresh_token_confidentiality_minute")
-	return r.Helper.CreateJwtToken(r.Config.GetString("api_app_base.refresh_token_secret"), string(refreshTokenJson), refreshTokenConfidentiality)
+	return time.Now().Add(time.Duration(refreshTokenConfidentiality) * time.Minute)
+}
+
+// revokeMemberExpiredAt is the time every token issued before now has expired
+func (r Controller) revokeMemberExpiredAt() time.Time {
+	confidentiality := r.Config.GetInt("api_app_base.token_confidentiality_minute")
+	refreshTokenConfidentiality := r.Config.GetInt("api_app_base.refresh_token_confidentiality_minute")
+	if refreshTokenConfidentiality > confidentiality {
+		confidentiality = refreshTokenConfidentiality
+	}
+	return time.Now().Add(time.Duration(confidentiality) * time.Minute)
+}
+
+// impersonationTokenMinute is the lifetime of an impersonation token, never longer than an access token
+func (r Controller) impersonationTokenMinute() int {
+	confidentiality := r.Config.GetInt("api_app_base.token_confidentiality_minute")
+	tokenMinute := r.Config.GetInt("impersonation.token_minute")
+	if tokenMinute <= 0 || tokenMinute > confidentiality {
+		return confidentiality
+	}
+	return tokenMinute
+}
+
+// stepUpMaxAge is how long after the last authentication a sensitive operation is allowed, 5 minutes by default
+func (r Controller) stepUpMaxAge() time.Duration {
+	maxAge := r.Config.GetInt("step_up.max_age_minute")
+	if maxAge <= 0 {
+		maxAge = 5
+	}
+	return time.Duration(maxAge) * time.Minute
+}
+
+// memberPhotoConfig read member_photo, the photo is limited to 2048 KB by default
+func (r Controller) memberPhotoConfig(ctx context.Context) entity.MemberPhotoConfig {
+	var thumbnailSizes []int
+	if err := r.Config.UnmarshalKey("member_photo.thumbnail_sizes", &thumbnailSizes); err != nil {
+		log.Error(ctx, "read member_photo.thumbnail_sizes : %s", err.Error())
+	}
+
+	maxSize := r.Config.GetInt("member_photo.max_size_kb")
+	if maxSize <= 0 {
+		maxSize = 2048
+	}
+
+	return entity.MemberPhotoConfig{
+		MaxSize:        int64(maxSize) * 1024,
+		ThumbnailSizes: thumbnailSizes,
+	}
+}
+
+func (r Controller) memberImportConfig() entity.MemberImportConfig {
+	return entity.MemberImportConfig{
+		MaxSize:   int64(r.Config.GetInt("member_import.max_size_kb")) * 1024,
+		MaxRows:   r.Config.GetInt("member_import.max_rows"),
+		BatchSize: r.Config.GetInt("member_import.batch_size"),
+	}.WithDefault()
+}
+
+// maxActiveSession is the number of concurrent sessions allowed for the member type,
+// 1 keep the single device policy and 0 means unlimited
+func (r Controller) maxActiveSession(memberType string) int {
+	key := fmt.Sprintf("session.max_active.%s", memberType)
+	if r.Config.GetString(key) == "" {
+		key = "session.max_active.default"
+	}
+	return r.Config.GetInt(key)
+}
+
+func (r Controller) loginProtectionConfig() entity.LoginProtectionConfig {
+	return entity.LoginProtectionConfig{
+		Enabled:                  r.Config.GetBool("login_protection.enabled"),
+		FreeAttempts:             r.Config.GetInt("login_protection.free_attempts"),
+		BaseDelay:                time.Duration(r.Config.GetInt("login_protection.base_delay_second")) * time.Second,
+		MaxDelay:                 time.Duration(r.Config.GetInt("login_protection.max_delay_second")) * time.Second,
+		Window:                   time.Duration(r.Config.GetInt("login_protection.window_minute")) * time.Minute,
+		LockoutDuration:          time.Duration(r.Config.GetInt("login_protection.lockout_duration_minute")) * time.Minute,
+		UsernameLockoutThreshold: r.Config.GetInt("login_protection.username_lockout_threshold"),
+		IpLockoutThreshold:       r.Config.GetInt("login_protection.ip_lockout_threshold"),
+	}
+}
+
+// requiredVerification read verification.required_for_login, the member type is the key
+// and the value is email, phone or any. The default key is used for the other member types
+func (r Controller) requiredVerification(ctx context.Context) map[string]string {
+	requirement := map[string]string{}
+	if err := r.Config.UnmarshalKey("verification.required_for_login", &requirement); err != nil {
+		log.Error(ctx, "read verification.required_for_login : %s", err.Error())
+	}
+	return requirement
+}
+
+func (r Controller) twoFactorConfig() entity.TwoFactorConfig {
+	return entity.TwoFactorConfig{
+		Issuer:            r.Config.GetString("two_factor.issuer"),
+		Skew:              r.Config.GetInt("two_factor.skew_step"),
+		RecoveryCodeCount: r.Config.GetInt("two_factor.recovery_code_count"),
+		ChallengeLifetime: time.Duration(r.Config.GetInt("two_factor.challenge_minute")) * time.Minute,
+	}
+}
+
+func (r Controller) oidcLoginConfig() entity.OidcLoginConfig {
+	return entity.OidcLoginConfig{
+		AllowCreate:   r.Config.GetBool("oidc.allow_create"),
+		MemberType:    r.Config.GetString("oidc.member_type"),
+		StateLifetime: time.Duration(r.Config.GetInt("oidc.state_minute")) * time.Minute,
+	}
+}
+
+func (r Controller) verificationConfig() entity.VerificationConfig {
+	return entity.VerificationConfig{
+		CodeLifetime:   time.Duration(r.Config.GetInt("verification.code_minute")) * time.Minute,
+		ResendInterval: time.Duration(r.Config.GetInt("verification.resend_second")) * time.Second,
+		MaxRequest:     r.Config.GetInt("verification.max_request"),
+		RequestWindow:  time.Duration(r.Config.GetInt("verification.request_window_minute")) * time.Minute,
+		MaxAttempt:     r.Config.GetInt("verification.max_attempt"),
+	}
+}
+
+// passwordlessConfig sign the magic link with the refresh secret when passwordless.link_secret is not set
+func (r Controller) passwordlessConfig(ctx context.Context) entity.PasswordlessConfig {
+	var memberTypes []string
+	if err := r.Config.UnmarshalKey("passwordless.member_type", &memberTypes); err != nil {
+		log.Error(ctx, "read passwordless.member_type : %s", err.Error())
+	}
+
+	linkSecret := r.Config.GetString("passwordless.link_secret")
+	if linkSecret == "" {
+		linkSecret = r.Config.GetString("api_app_base.refresh_secret_token")
+	}
+
+	return entity.PasswordlessConfig{
+		LinkUrl:        r.Config.GetString("passwordless.link_url"),
+		LinkSecret:     linkSecret,
+		LinkLifetime:   time.Duration(r.Config.GetInt("passwordless.link_minute")) * time.Minute,
+		CodeLifetime:   time.Duration(r.Config.GetInt("passwordless.code_minute")) * time.Minute,
+		ResendInterval: time.Duration(r.Config.GetInt("passwordless.resend_second")) * time.Second,
+		MaxAttempt:     r.Config.GetInt("passwordless.max_attempt"),
+		MemberTypes:    memberTypes,
+	}
+}
+
+// sendLoginLockedError answer 429 with Retry-After when err is a LoginLockedError
+func (r Controller) sendLoginLockedError(c *gin.Context, err error, traceID string) bool {
+	var lockedErr entity.LoginLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
+	}
+
+	retryAfter := lockedErr.RetryAfterSecond()
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.AbortWithStatus(http.StatusTooManyRequests)
+	r.Helper.SendError(c, lockedErr.Error(), map[string]interface{}{
+		"code":        lockedErr.Code(),
+		"retry_after": retryAfter,
+	}, http.StatusTooManyRequests, "tooManyRequests", traceID)
+
+	return true
+}
+
+// sendPasswordPolicyError answer the rules the password does not follow, by field
+func (r Controller) sendPasswordPolicyError(c *gin.Context, err error, traceID string) bool {
+	var policyErr entity.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	r.Helper.SendBadRequest(c, policyErr.Error(), map[string]interface{}{
+		"code":   policyErr.Code(),
+		"fields": policyErr.Fields(),
+	}, traceID)
+
+	return true
+}
+
+// recordAuditEvent fill the actor and the client from the request. Its error is only logged,
+// the action is already done
+func (r Controller) recordAuditEvent(ctx context.Context, c *gin.Context, inputPort recordauditeventv1.Inport, traceID string, req entity.AuditEventReq) {
+	if req.ActorId == "" {
+		if apiKey, err := r.getApiKeyFromContext(c); err == nil {
+			req.ActorType, req.ActorId = entity.AuditActorApiKey, apiKey.ID
+		} else if claims, err := r.Helper.GetMemberClaimsFromContext(c); err == nil {
+			req.ActorType, req.ActorId = entity.AuditActorMember, claims.Subject
+		}
+	} else if req.ActorType == "" {
+		req.ActorType = entity.AuditActorMember
+	}
+
+	// the admin is the actor of what it does in the name of the member
+	if impersonatedBy, impersonated := r.getImpersonatorFromContext(c); impersonated {
+		metadata := map[string]string{"impersonated_member_id": req.ActorId}
+		for key, value := range req.Metadata {
+			metadata[key] = value
+		}
+		req.ActorType, req.ActorId, req.Metadata = entity.AuditActorMember, impersonatedBy, metadata
+	}
+
+	req.IpAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	req.TraceId = traceID
+
+	if _, err := inputPort.Execute(ctx, req); err != nil {
+		log.Error(ctx, err.Error())
+	}
+}
+
+// getApiKeyFromContext return the key loaded by the api key interceptor
+func (r Controller) getApiKeyFromContext(c *gin.Context) (*entity.ApiKeyData, error) {
+	apiKeyFromContext, _ := c.Get(apiKeyContextKey)
+	apiKey, ok := apiKeyFromContext.(*entity.ApiKeyData)
+	if !ok || apiKey == nil {
+		return nil, fmt.Errorf("api key not exist in api key context")
+	}
+
+	return apiKey, nil
+}
+
+// getImpersonatorFromContext return the admin acting as the member, it is set by the authorized interceptor
+func (r Controller) getImpersonatorFromContext(c *gin.Context) (string, bool) {
+	impersonatedBy := c.GetString(impersonatedByContextKey)
+	return impersonatedBy, impersonatedBy != ""
+}
+
+// getGrantedPermissionsFromContext return the permissions stored by the permission interceptor
+func (r Controller) getGrantedPermissionsFromContext(c *gin.Context) []string {
+	permissionsFromContext, _ := c.Get("permissions")
+	permissions, _ := permissionsFromContext.([]string)
+	return permissions
+}
+
+// getMemberFromContext return the member loaded by the authorized interceptor
+func (r Controller) getMemberFromContext(c *gin.Context) (*entity.MemberDataShown, error) {
+	memberFromContext, _ := c.Get("member")
+	member, ok := memberFromContext.(*entity.MemberDataShown)
+	if !ok || member == nil {
+		return nil, fmt.Errorf("member not exist in member context")
+	}
+
+	return member, nil
 }
@@ -2,49 +2,50 @@ package apibaseappcontroller
 
 import (
 	"backend_base_app/domain/domerror"
+	"backend_base_app/domain/entity"
 	appMiddleware "backend_base_app/lib/wrapper/middleware"
+	"backend_base_app/shared/helper"
 	"backend_base_app/shared/log"
 	"backend_base_app/shared/util"
+	"backend_base_app/usecase/apikey/v1/checkapikeyv1"
+	"backend_base_app/usecase/authorization/v1/checkrevokedtokenv1"
+	"backend_base_app/usecase/authorization/v1/checksessionv1"
+	"backend_base_app/usecase/authorization/v1/getmemberpermissionv1"
 	"backend_base_app/usecase/member/v1/getmemberv1"
 
 	"context"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
 )
 
-type userContext struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	Fullname string `json:"fullname"`
-	Rolename string `json:"role"`
+type authorizedInport struct {
+	member       getmemberv1.Inport
+	revokedToken checkrevokedtokenv1.Inport
+	session      checksessionv1.Inport
 }
 
 // authorized is an interceptor
-func (r *Controller) authorized(inputPort getmemberv1.Inport) gin.HandlerFunc {
+func (r *Controller) authorized(inputPort authorizedInport) gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 
 		traceID := util.GenerateID()
 		ctx := log.Context(c.Request.Context(), traceID)
 
-		authorizationHeader := c.Request.Header.Get("Authorization")
-		if !strings.Contains(authorizationHeader, "Bearer") {
-			newErr := domerror.FailUnmarshalRequestBodyError
-			c.AbortWithStatus(http.StatusBadRequest)
-			r.Helper.SendBadRequest(c, "Request doesn't contains authorization tokenClaim", newErr, traceID)
+		tokenString, ok := r.requestSessionToken(c, accessTokenCookie, "Request doesn't contains authorization tokenClaim", traceID)
+		if !ok {
 			return
 		}
 
-		tokenString := strings.Replace(authorizationHeader, "Bearer ", "", -1)
-
-		tokenClaim, err := appMiddleware.GetMapClaimByKeyJwtToken(r.Config.GetString("handlingsolution.secret"), tokenString, "jti")
+		claims, err := r.parseMemberToken(r.KeySet, helper.TokenTypeAccess, tokenString)
 		if err != nil {
-			fmt.Println("Error ValidateTokenHandler GetMapClaimByKeyJwtToken jti : ", err)
-			if err.Error() == "Token is expired" {
+			log.Error(ctx, "parse member token : %s", err.Error())
+			if errors.Is(err, jwt.ErrTokenExpired) {
 				c.AbortWithStatus(http.StatusUnauthorized)
 			} else {
 				c.AbortWithStatus(http.StatusBadRequest)
@@ -53,28 +54,10 @@ func (r *Controller) authorized(inputPort getmemberv1.Inport) gin.HandlerFunc {
 			return
 		}
 
-		c.Set("user", tokenClaim)
+		r.Helper.SetMemberClaims(c, claims)
 		c.Set("tokenstring", tokenString)
 
-		if tokenClaim == nil {
-			c.AbortWithStatus(http.StatusUnauthorized)
-			r.Helper.SendUnauthorizedError(c, "Unauthorized Access", r.Helper.EmptyJsonMap(), traceID)
-			return
-		}
-
-		tokenClaimStr, ok := tokenClaim.(string)
-		if !ok {
-			// Type assertion failed, handle the error
-			fmt.Println("Failed to convert tokenClaim to string")
-			// Handle the error
-			return
-		}
-
-		fmt.Println("CHECK NORMAL TOKEN ", tokenClaimStr)
-
-		authorized, statusCode, messageResponse := checkAuthorizedAccount(
-			ctx, tokenClaimStr, inputPort,
-		)
+		member, authorized, statusCode, messageResponse := checkAuthorizedAccount(ctx, claims, inputPort)
 
 		if !authorized {
 			if statusCode == -1 {
@@ -87,58 +70,74 @@ func (r *Controller) authorized(inputPort getmemberv1.Inport) gin.HandlerFunc {
 			r.Helper.SendUnauthorizedError(c, messageResponse, r.Helper.EmptyJsonMap(), traceID)
 			return
 		}
+
+		c.Set("member", member)
+		if claims.IsImpersonated() {
+			c.Set(impersonatedByContextKey, claims.GetActorId())
+		}
 		return
 	}
 }
 
-// authorizedRefreshToken is an interceptor
-func (r *Controller) authorizedRefreshToken(inputPort getmemberv1.Inport) gin.HandlerFunc {
+// notImpersonated is an interceptor, it must be placed after authorized.
+// It protect the operations an admin must not do in the name of the member
+func (r *Controller) notImpersonated() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 
-		traceID := util.GenerateID()
-		ctx := log.Context(c.Request.Context(), traceID)
-
-		authorizationHeader := c.Request.Header.Get("Authorization")
-		if !strings.Contains(authorizationHeader, "Bearer") {
-			newErr := domerror.FailUnmarshalRequestBodyError
-			c.AbortWithStatus(http.StatusBadRequest)
-			r.Helper.SendBadRequest(c, "Request doesn't contains authorization Bearer", newErr, traceID)
+		if _, impersonated := r.getImpersonatorFromContext(c); impersonated {
+			traceID := util.GenerateID()
+			c.AbortWithStatus(http.StatusForbidden)
+			r.Helper.SendForbiddenError(c, OperationNotAllowedWhileImpersonating.Error(), r.Helper.EmptyJsonMap(), traceID)
 			return
 		}
+	}
+}
+
+// recentAuthentication is an interceptor, it must be placed after authorized.
+// It refuse the token whose last authentication is older than maxAge, the member has to reauthenticate
+func (r *Controller) recentAuthentication(maxAge time.Duration) gin.HandlerFunc {
 
-		tokenString := strings.Replace(authorizationHeader, "Bearer ", "", -1)
+	return func(c *gin.Context) {
 
-		tokenClaim, err := appMiddleware.GetMapClaimByKeyJwtToken(r.Config.GetString("handlingsolution.refresh_token_secret"), tokenString, "jti")
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err == nil {
+			err = entity.CheckRecentAuthentication(claims.GetAuthTime(), maxAge, time.Now())
+		}
 		if err != nil {
-			fmt.Println("Error ValidateTokenHandler GetMapClaimByKeyJwtToken jti : ", err)
+			traceID := util.GenerateID()
 			c.AbortWithStatus(http.StatusUnauthorized)
 			r.Helper.SendUnauthorizedError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
 			return
 		}
+	}
+}
 
-		c.Set("user", tokenClaim)
-		c.Set("tokenstring", tokenString)
+// authorizedRefreshToken is an interceptor
+func (r *Controller) authorizedRefreshToken(inputPort authorizedInport) gin.HandlerFunc {
 
-		if tokenClaim == nil {
-			c.AbortWithStatus(http.StatusForbidden)
-			r.Helper.SendForbiddenError(c, "Forbidden Access", r.Helper.EmptyJsonMap(), traceID)
+	return func(c *gin.Context) {
+
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
+
+		tokenString, ok := r.requestSessionToken(c, refreshTokenCookie, "Request doesn't contains authorization Bearer", traceID)
+		if !ok {
 			return
 		}
 
-		tokenClaimStr, ok := tokenClaim.(string)
-		if !ok {
-			// Type assertion failed, handle the error
-			fmt.Println("Failed to convert tokenClaim to string")
-			// Handle the error
+		claims, err := r.parseMemberToken(r.RefreshKeySet, helper.TokenTypeRefresh, tokenString)
+		if err != nil {
+			log.Error(ctx, "parse member token : %s", err.Error())
+			c.AbortWithStatus(http.StatusUnauthorized)
+			r.Helper.SendUnauthorizedError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
 			return
 		}
 
-		fmt.Println("CHECK RERESH_TOKEN ", tokenClaimStr)
+		r.Helper.SetMemberClaims(c, claims)
+		c.Set("tokenstring", tokenString)
 
-		authorized, statusCode, messageResponse := checkAuthorizedAccount(
-			ctx, tokenClaimStr, inputPort,
-		)
+		_, authorized, statusCode, messageResponse := checkAuthorizedAccount(ctx, claims, inputPort)
 
 		if !authorized {
 			if statusCode == -1 {
@@ -155,86 +154,189 @@ func (r *Controller) authorizedRefreshToken(inputPort getmemberv1.Inport) gin.Ha
 	}
 }
 
+// requestSessionToken read the bearer token, or the cookie of the cookie mode when there is no Authorization header.
+// It answer the request and return false when there is no valid token
+func (r *Controller) requestSessionToken(c *gin.Context, cookieName string, missingMessage string, traceID string) (string, bool) {
+	authorizationHeader := c.Request.Header.Get("Authorization")
+	if strings.Contains(authorizationHeader, "Bearer") {
+		return strings.Replace(authorizationHeader, "Bearer ", "", -1), true
+	}
+
+	tokenString, err := r.cookieSessionToken(c, cookieName)
+	if err != nil {
+		if errors.Is(err, CsrfTokenInvalid) {
+			c.AbortWithStatus(http.StatusForbidden)
+			r.Helper.SendForbiddenError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+			return "", false
+		}
+
+		newErr := domerror.FailUnmarshalRequestBodyError
+		c.AbortWithStatus(http.StatusBadRequest)
+		r.Helper.SendBadRequest(c, missingMessage, newErr, traceID)
+		return "", false
+	}
+
+	return tokenString, true
+}
+
 func checkAuthorizedAccount(
 	ctx context.Context,
-	tokenClaimStr string,
-	inputPort getmemberv1.Inport,
-) (bool, int, string) {
-	// Unmarshal the tokenClaimStr into a map
-	var claimMap map[string]interface{}
-	err := json.Unmarshal([]byte(tokenClaimStr), &claimMap)
+	claims *helper.MemberClaims,
+	inputPort authorizedInport,
+) (*entity.MemberDataShown, bool, int, string) {
+	id := claims.Subject
+	if id == "" {
+		return nil, false, http.StatusUnauthorized, "Failed to retrieve authorization subject claim"
+	}
+
+	revoked, err := inputPort.revokedToken.Execute(ctx, entity.CheckRevokedTokenReq{
+		MemberId: id,
+		TokenId:  claims.ID,
+		IssuedAt: claims.GetIssuedAt(),
+	})
 	if err != nil {
-		// Failed to unmarshal, handle the error
-		fmt.Println("Failed to unmarshal tokenClaim:", err)
-		// Handle the error
-		return false, -1, ""
+		return nil, false, http.StatusInternalServerError, err.Error()
+	}
+	if revoked {
+		return nil, false, http.StatusUnauthorized, entity.TokenHasBeenRevoked.Error()
 	}
 
-	fmt.Println("TAG ClaimMap >>> ", claimMap)
+	// an impersonation token belong to the session of the admin, it ends with it
+	sessionMemberId := id
+	if claims.IsImpersonated() {
+		sessionMemberId = claims.GetActorId()
+
+		revoked, err := inputPort.revokedToken.Execute(ctx, entity.CheckRevokedTokenReq{
+			MemberId: sessionMemberId,
+			TokenId:  claims.ID,
+			IssuedAt: claims.GetIssuedAt(),
+		})
+		if err != nil {
+			return nil, false, http.StatusInternalServerError, err.Error()
+		}
+		if revoked {
+			return nil, false, http.StatusUnauthorized, entity.TokenHasBeenRevoked.Error()
+		}
+	}
 
-	deviceId, ok := claimMap["id_device"].(string)
-	if !ok {
-		fmt.Println("TAG DEVICE ID NOT FOUND")
-		// Handle the error if the value is not of the expected type
-		// Or assign a default value if needed
-		// c.AbortWithStatus(http.StatusInternalServerError)
-		// r.Helper.SendNotFoundError(c, "Failed to retrieve authorization id claim", nil, traceID)
-		return false, http.StatusInternalServerError, "Failed to retrieve authorization id claim"
+	// token without session are issued before sessions exist, the member has to login again
+	_, err = inputPort.session.Execute(ctx, entity.CheckSessionReq{
+		MemberId:  sessionMemberId,
+		SessionId: claims.SessionId,
+	})
+	if err != nil {
+		return nil, false, http.StatusUnauthorized, err.Error()
 	}
 
-	id, ok := claimMap["id"].(string)
-	if !ok {
-		// Handle the error if the value is not of the expected type
-		// Or assign a default value if needed
-		// c.AbortWithStatus(http.StatusInternalServerError)
-		// r.Helper.SendNotFoundError(c, "Failed to retrieve authorization id claim", nil, traceID)
-		return false, http.StatusInternalServerError, "Failed to retrieve authorization id claim"
+	courierData, err := inputPort.member.Execute(ctx, id)
+	if err != nil {
+		// the member roles are needed by the next interceptor, so the token is useless without its member
+		return nil, false, http.StatusUnauthorized, err.Error()
 	}
 
-	courierData, err := inputPort.Execute(ctx, id)
+	// a token issued before the last password change carry an older credential version
+	if claims.CredentialVersion < courierData.CredentialVersion {
+		return nil, false, http.StatusUnauthorized, CredentialVersionOutdated.Error()
+	}
 
 	authorized := true
 	statusCode := -1
 	messageResponse := ""
 
-	if err == nil {
-		fmt.Println("TAG COURIERDATA ", courierData.IsSuspend, " || ", courierData.DeviceId, " == ", deviceId)
-		if courierData.IsSuspend == true {
-			authorized = false
-			statusCode = http.StatusForbidden
-			messageResponse = UserSuspended.Error()
-		}
-
-		if courierData.DeviceId != deviceId {
-			fmt.Println("TAG ClaimMap WHEN COMPARE >>> ", claimMap)
-			authorized = false
-			statusCode = http.StatusForbidden
-			messageResponse = UserLoginInOtherDevice.Error()
-		}
+	if courierData.IsSuspend == true {
+		authorized = false
+		statusCode = http.StatusForbidden
+		messageResponse = UserSuspended.Error()
 	}
 
-	fmt.Println("TAG authorized ", authorized, messageResponse)
+	return &courierData, authorized, statusCode, messageResponse
+}
+
+// apiKeyAuthorized is an interceptor, the key is sent in the api-key header
+func (r *Controller) apiKeyAuthorized(inputPort checkapikeyv1.Inport) gin.HandlerFunc {
+
+	return appMiddleware.KeyAuthWithConfig(appMiddleware.KeyAuthConfig{
+		KeyLookup: apiKeyLookup,
+		Validator: func(c *gin.Context, key string) (bool, error) {
+			traceID := util.GenerateID()
+			ctx := log.Context(c.Request.Context(), traceID)
+
+			apiKey, err := inputPort.Execute(ctx, key)
+			if err != nil {
+				log.Error(ctx, err.Error())
+				return false, err
+			}
+
+			c.Set(apiKeyContextKey, apiKey)
+			return true, nil
+		},
+		ErrorHandler: func(c *gin.Context, err error) {
+			traceID := util.GenerateID()
 
-	return authorized, statusCode, messageResponse
+			if errors.Is(err, appMiddleware.ErrKeyAuthMissing) {
+				c.AbortWithStatus(http.StatusBadRequest)
+				r.Helper.SendBadRequest(c, domerror.ApiKeyIsNeeded.Error(), r.Helper.EmptyJsonMap(), traceID)
+				return
+			}
+			c.AbortWithStatus(http.StatusUnauthorized)
+			r.Helper.SendUnauthorizedError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+		},
+	})
 }
 
-func (r *Controller) superadminAuth(c *gin.Context) error {
+// permissionRequired is an interceptor, it must be placed after authorized or apiKeyAuthorized.
+// A request authorized by an api key is checked against the key scopes instead of the member roles
+func (r *Controller) permissionRequired(inputPort getmemberpermissionv1.Inport, permissions []string) gin.HandlerFunc {
 
-	var userCtx userContext
+	return func(c *gin.Context) {
 
-	err := json.Unmarshal([]byte(c.GetString("user")), &userCtx)
-	if err != nil {
-		return domerror.FailUnmarshalResponseBodyError
-	}
+		traceID := util.GenerateID()
+		ctx := log.Context(c.Request.Context(), traceID)
 
-	fmt.Printf("User: %s", userCtx.Rolename)
-	if userCtx.Rolename != "Superadmin" {
-		return InsufficientRole
-	}
+		if apiKey, err := r.getApiKeyFromContext(c); err == nil {
+			for _, permission := range permissions {
+				if !apiKey.HasScope(permission) {
+					c.AbortWithStatus(http.StatusForbidden)
+					r.Helper.SendForbiddenError(c, InsufficientScope.Error(), r.Helper.EmptyJsonMap(), traceID)
+					return
+				}
+			}
+
+			c.Set("permissions", apiKey.Scopes)
+			return
+		}
+
+		member, err := r.getMemberFromContext(c)
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			r.Helper.SendUnauthorizedError(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+			return
+		}
+
+		grantedPermissions, err := inputPort.Execute(ctx, member.GetRoles())
+		if err != nil {
+			log.Error(ctx, err.Error())
+			c.AbortWithStatus(http.StatusInternalServerError)
+			r.Helper.SendBadRequest(c, err.Error(), r.Helper.EmptyJsonMap(), traceID)
+			return
+		}
+
+		granted := entity.RoleData{Permissions: grantedPermissions}
+		for _, permission := range permissions {
+			if !granted.HasPermission(permission) {
+				c.AbortWithStatus(http.StatusForbidden)
+				r.Helper.SendForbiddenError(c, InsufficientRole.Error(), r.Helper.EmptyJsonMap(), traceID)
+				return
+			}
+		}
 
-	return nil
+		c.Set("permissions", grantedPermissions)
+	}
 }
 
 const UserSuspended domerror.ErrorType = "ER1006 User is Suspended"
-const UserLoginInOtherDevice domerror.ErrorType = "ER1006 Device Already Login in other device"
 const InsufficientRole domerror.ErrorType = "ER1009 make sure your role has sufficient authorities"
+const InsufficientScope domerror.ErrorType = "ER1009 make sure your api key has sufficient scopes"
+const CsrfTokenInvalid domerror.ErrorType = "ER1021 csrf token is missing or invalid"
+const CredentialVersionOutdated domerror.ErrorType = "ER1023 the password has changed since the token was issued, login again"
+const OperationNotAllowedWhileImpersonating domerror.ErrorType = "ER1019 this operation is not allowed while impersonating a member"
@@ -2,52 +2,189 @@ package apibaseappcontroller
 
 import (
 	cfg "backend_base_app/config/env"
+	"backend_base_app/domain/entity"
 	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/lib/core/jwtkey"
+	"backend_base_app/lib/core/storage"
 	"backend_base_app/shared/helper"
+	"backend_base_app/usecase/apikey/v1/checkapikeyv1"
+	"backend_base_app/usecase/authorization/v1/checkrevokedtokenv1"
+	"backend_base_app/usecase/authorization/v1/checksessionv1"
+	"backend_base_app/usecase/authorization/v1/getmemberpermissionv1"
 	"backend_base_app/usecase/member/v1/getmemberv1"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	apiKeyHeader     = "api-key"
+	apiKeyLookup     = "header:" + apiKeyHeader
+	apiKeyContextKey = "api_key"
+	// impersonatedByContextKey hold the admin acting as the member of the token
+	impersonatedByContextKey = "impersonated_by"
+)
+
 type Controller struct {
 	Router     gin.IRouter
 	Helper     helper.HTTPHelper
 	Config     cfg.Config
 	DataSource *apibaseappgateway.GatewayApiBaseApp
+	KeySet     *jwtkey.KeySet
+	// RefreshKeySet only hold the refresh token secret, refresh token are verified by this service only
+	RefreshKeySet *jwtkey.KeySet
+}
+
+func (r *Controller) newAuthorizedInport() authorizedInport {
+	return authorizedInport{
+		member:       getmemberv1.NewUsecase(r.DataSource),
+		revokedToken: checkrevokedtokenv1.NewUsecase(r.DataSource),
+		session:      checksessionv1.NewUsecase(r.DataSource),
+	}
 }
 
 func (r *Controller) handlerAuthMember() gin.HandlerFunc {
-	inputPort := getmemberv1.NewUsecase(r.DataSource)
-	return r.authorized(inputPort)
+	return r.authorized(r.newAuthorizedInport())
 }
 
 func (r *Controller) handlerRefreshAuth() gin.HandlerFunc {
-	inputPort := getmemberv1.NewUsecase(r.DataSource)
-	return r.authorizedRefreshToken(inputPort)
+	return r.authorizedRefreshToken(r.newAuthorizedInport())
+}
+
+func (r *Controller) handlerApiKey() gin.HandlerFunc {
+	return r.apiKeyAuthorized(checkapikeyv1.NewUsecase(r.DataSource))
+}
+
+// handlerAuthMemberOrApiKey accept an api key when the api-key header is sent, a member token otherwise.
+// The handler behind it must not need the member
+func (r *Controller) handlerAuthMemberOrApiKey() gin.HandlerFunc {
+	authMember := r.handlerAuthMember()
+	apiKey := r.handlerApiKey()
+
+	return func(c *gin.Context) {
+		if c.GetHeader(apiKeyHeader) != "" {
+			apiKey(c)
+			return
+		}
+		authMember(c)
+	}
+}
+
+// handlerNotImpersonated must be placed after handlerAuthMember
+func (r *Controller) handlerNotImpersonated() gin.HandlerFunc {
+	return r.notImpersonated()
+}
+
+// handlerSelfOrPermission must be placed after handlerAuthMember, a member act on its own :id
+// without the permissions
+func (r *Controller) handlerSelfOrPermission(permissions ...string) gin.HandlerFunc {
+	permission := r.handlerPermission(permissions...)
+
+	return func(c *gin.Context) {
+		claims, err := r.Helper.GetMemberClaimsFromContext(c)
+		if err == nil && claims.Subject == c.Param("id") {
+			return
+		}
+		permission(c)
+	}
+}
+
+// handlerRecentAuth must be placed after handlerAuthMember, it protect the sensitive operations
+func (r *Controller) handlerRecentAuth() gin.HandlerFunc {
+	return r.recentAuthentication(r.stepUpMaxAge())
+}
+
+// handlerPermission must be placed after handlerAuthMember
+func (r *Controller) handlerPermission(permissions ...string) gin.HandlerFunc {
+	inputPort := getmemberpermissionv1.NewUsecase(r.DataSource)
+	return r.permissionRequired(inputPort, permissions)
 }
 
 func (r *Controller) RegisterRouter() {
+	r.Router.GET("/.well-known/jwks.json", ApiBaseAppJwks(r))
+
 	group := r.Router.Group("/api")
 	r.RegisterGroupV1(group)
+
+	r.RegisterStorage()
+
+	r.RegisterScheduler()
+}
+
+// RegisterStorage serve the directory of the local storage when storage.local.serve_path is set,
+// the s3 objects are served by the bucket
+func (r *Controller) RegisterStorage() {
+	backend := r.Config.GetString("storage.backend")
+	servePath := r.Config.GetString("storage.local.serve_path")
+	if (backend != "" && backend != storage.BackendLocal) || servePath == "" {
+		return
+	}
+
+	r.Router.Static(servePath, r.Config.GetString("storage.local.dir"))
 }
 
 func (r *Controller) RegisterGroupV1(groupParent *gin.RouterGroup) {
 	group := groupParent.Group("/v1")
 	r.RegisterGroupV1Auth(group)
 	r.RegisterGroupV1Member(group)
+	r.RegisterGroupV1Admin(group)
 }
 
 func (r *Controller) RegisterGroupV1Auth(groupParent *gin.RouterGroup) {
 	group := groupParent.Group("/auth")
 
 	group.POST("/login", ApiBaseAppAuthMember(r))
+	group.POST("/login/2fa", ApiBaseAppAuthTwoFactor(r))
 	group.POST("/refresh", r.handlerRefreshAuth(), ApiBaseRefreshAuthMember(r))
+	group.POST("/logout", r.handlerAuthMember(), ApiBaseAppLogoutMember(r))
+	group.POST("/logout-all", r.handlerAuthMember(), r.handlerNotImpersonated(), ApiBaseAppLogoutAllMember(r))
+	group.GET("/sessions", r.handlerAuthMember(), ApiBaseAppSessionFindAll(r))
+	group.DELETE("/sessions/:id", r.handlerAuthMember(), r.handlerNotImpersonated(), ApiBaseAppSessionRevoke(r))
+	group.POST("/password/forgot", ApiBaseAppForgotPassword(r))
+	group.POST("/password/reset", ApiBaseAppResetPassword(r))
+	group.POST("/verify/request", ApiBaseAppRequestVerification(r))
+	group.POST("/verify/confirm", ApiBaseAppConfirmVerification(r))
+	group.POST("/2fa/enroll", r.handlerAuthMember(), r.handlerNotImpersonated(), ApiBaseAppTwoFactorEnroll(r))
+	group.POST("/2fa/confirm", r.handlerAuthMember(), r.handlerNotImpersonated(), ApiBaseAppTwoFactorConfirm(r))
+	group.POST("/2fa/disable", r.handlerAuthMember(), r.handlerNotImpersonated(), r.handlerRecentAuth(), ApiBaseAppTwoFactorDisable(r))
+	group.POST("/reauthenticate", r.handlerAuthMember(), r.handlerNotImpersonated(), ApiBaseAppReauthenticate(r))
+	group.POST("/passwordless/start", ApiBaseAppPasswordlessStart(r))
+	group.POST("/passwordless/complete", ApiBaseAppPasswordlessComplete(r))
+	group.GET("/oidc/:provider/start", ApiBaseAppOidcStart(r))
+	group.GET("/oidc/:provider/callback", ApiBaseAppOidcCallback(r))
 }
 
 func (r *Controller) RegisterGroupV1Member(groupParent *gin.RouterGroup) {
 	group := groupParent.Group("/member")
 
 	group.POST("/create", ApiBaseAppMemberCreate(r))
-	group.GET("", r.handlerAuthMember(), ApiBaseAppMemberFindAll(r))
-	group.GET("/:id", r.handlerAuthMember(), ApiBaseAppMemberFindOne(r))
+	group.POST("/me/password", r.handlerAuthMember(), r.handlerNotImpersonated(), ApiBaseAppChangePassword(r))
+	group.GET("", r.handlerAuthMemberOrApiKey(), r.handlerPermission(entity.PermissionMemberRead), ApiBaseAppMemberFindAll(r))
+	group.GET("/:id", r.handlerAuthMemberOrApiKey(), r.handlerPermission(entity.PermissionMemberRead), ApiBaseAppMemberFindOne(r))
+	group.PATCH("/:id", r.handlerAuthMember(), r.handlerSelfOrPermission(entity.PermissionMemberWrite), ApiBaseAppMemberUpdate(r))
+	group.DELETE("/:id", r.handlerAuthMember(), r.handlerSelfOrPermission(entity.PermissionMemberWrite), ApiBaseAppMemberDelete(r))
+	group.PUT("/:id/photo", r.handlerAuthMember(), r.handlerSelfOrPermission(entity.PermissionMemberWrite), ApiBaseAppMemberPhotoUpload(r))
+	group.POST("/:id/restore", r.handlerAuthMember(), r.handlerNotImpersonated(), r.handlerPermission(entity.PermissionMemberWrite), ApiBaseAppMemberRestore(r))
+}
+
+func (r *Controller) RegisterGroupV1Admin(groupParent *gin.RouterGroup) {
+	// an admin act as itself, never through the token of an impersonated member
+	group := groupParent.Group("/admin", r.handlerAuthMember(), r.handlerNotImpersonated())
+
+	group.GET("/role", r.handlerPermission(entity.PermissionRoleRead), ApiBaseAppRoleFindAll(r))
+	group.POST("/role", r.handlerPermission(entity.PermissionRoleWrite), ApiBaseAppRoleCreate(r))
+	group.PUT("/role/:id", r.handlerPermission(entity.PermissionRoleWrite), ApiBaseAppRoleUpdate(r))
+	group.DELETE("/role/:id", r.handlerPermission(entity.PermissionRoleWrite), ApiBaseAppRoleDelete(r))
+	group.GET("/permission", r.handlerPermission(entity.PermissionRoleRead), ApiBaseAppPermissionFindAll(r))
+	group.POST("/member/import", r.handlerPermission(entity.PermissionMemberWrite), ApiBaseAppMemberImport(r))
+	group.PUT("/member/:id/role", r.handlerPermission(entity.PermissionRoleWrite), ApiBaseAppMemberAssignRole(r))
+	group.POST("/member/:id/suspend", r.handlerPermission(entity.PermissionMemberWrite), ApiBaseAppMemberSuspend(r))
+	group.POST("/member/:id/unsuspend", r.handlerPermission(entity.PermissionMemberWrite), ApiBaseAppMemberUnsuspend(r))
+	group.POST("/member/:id/impersonate", r.handlerPermission(entity.PermissionAll), ApiBaseAppMemberImpersonate(r))
+	group.DELETE("/member/:id/purge", r.handlerPermission(entity.PermissionMemberPurge), ApiBaseAppMemberPurge(r))
+	group.GET("/lockout", r.handlerPermission(entity.PermissionMemberRead), ApiBaseAppLockoutFindAll(r))
+	group.DELETE("/lockout/:id", r.handlerPermission(entity.PermissionMemberWrite), ApiBaseAppLockoutDelete(r))
+	group.GET("/api-key", r.handlerPermission(entity.PermissionApiKeyRead), ApiBaseAppApiKeyFindAll(r))
+	group.POST("/api-key", r.handlerPermission(entity.PermissionApiKeyWrite), ApiBaseAppApiKeyCreate(r))
+	group.DELETE("/api-key/:id", r.handlerPermission(entity.PermissionApiKeyWrite), ApiBaseAppApiKeyRevoke(r))
+	group.GET("/audit-event", r.handlerPermission(entity.PermissionAuditRead), ApiBaseAppAuditEventFindAll(r))
 }
@@ -0,0 +1,56 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/member/v1/liftsuspensionv1"
+	"context"
+	"time"
+)
+
+// RegisterScheduler start the background jobs of the service, they run until the process stops
+func (r *Controller) RegisterScheduler() {
+	go r.runSuspensionSweep()
+}
+
+// runSuspensionSweep lift the suspensions whose suspended_until has passed. Every instance may run it,
+// a suspension is only lifted and audited once
+func (r *Controller) runSuspensionSweep() {
+	var inputPort = liftsuspensionv1.NewUsecase(r.DataSource)
+	var auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+
+	interval := r.Config.GetInt("member_suspension.sweep_interval_minute")
+	if interval <= 0 {
+		interval = 1
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		traceID := util.GenerateID()
+		ctx := log.Context(context.Background(), traceID)
+
+		res, err := inputPort.Execute(ctx)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			continue
+		}
+
+		for _, lifted := range res {
+			_, err := auditInputPort.Execute(ctx, entity.AuditEventReq{
+				Action:         entity.AuditActionMemberUnsuspend,
+				TargetMemberId: lifted.Member.ID,
+				ActorType:      entity.AuditActorSystem,
+				TraceId:        traceID,
+				Metadata:       map[string]string{"reason": "suspended_until has passed"},
+				Changes:        entity.NewAuditChanges(lifted.Previous.Suspension(), lifted.Member.Suspension()),
+			})
+			if err != nil {
+				log.Error(ctx, err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package apibaseappcontroller
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// sessionModeHeader ask the login to answer the tokens in cookies, for browser clients
+	sessionModeHeader = "X-Session-Mode"
+	sessionModeCookie = "cookie"
+	csrfTokenHeader   = "X-CSRF-Token"
+
+	accessTokenCookie  = "access_token"
+	refreshTokenCookie = "refresh_token"
+	csrfTokenCookie    = "csrf_token"
+	// refreshTokenCookiePath limit the refresh token to the auth routes, refresh and logout
+	refreshTokenCookiePath = "/api/v1/auth"
+
+	// sessionCookieContextKey is set when the token of the request is read from a cookie
+	sessionCookieContextKey = "session_cookie"
+)
+
+type sessionCookieConfig struct {
+	Enabled  bool
+	Domain   string
+	SameSite http.SameSite
+}
+
+func (r Controller) sessionCookieConfig() sessionCookieConfig {
+	sameSite := http.SameSiteLaxMode
+	switch strings.ToLower(r.Config.GetString("session_cookie.same_site")) {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+
+	return sessionCookieConfig{
+		Enabled:  r.Config.GetBool("session_cookie.enabled"),
+		Domain:   r.Config.GetString("session_cookie.domain"),
+		SameSite: sameSite,
+	}
+}
+
+// isCookieSession is true when the client ask the cookie mode or send its token in a cookie
+func (r Controller) isCookieSession(c *gin.Context) bool {
+	if !r.sessionCookieConfig().Enabled {
+		return false
+	}
+	return c.GetHeader(sessionModeHeader) == sessionModeCookie || c.GetBool(sessionCookieContextKey)
+}
+
+// sendMemberAuth answer the tokens in the body, or in HttpOnly cookies for the cookie mode.
+// The cookie mode answer the csrf token instead, the client send it back in the X-CSRF-Token header.
+// Without refresh token, like after a reauthentication, only the access token cookie is replaced
+func (r Controller) sendMemberAuth(c *gin.Context, res entity.MemberResAuth, traceID string) {
+	if r.isCookieSession(c) && res.RefreshToken == "" {
+		tokenMaxAge := r.Config.GetInt("api_app_base.token_confidentiality_minute") * 60
+		setSessionCookie(c, r.sessionCookieConfig(), accessTokenCookie, res.Token, "/", tokenMaxAge, true)
+		res.Token = ""
+	} else if r.isCookieSession(c) {
+		csrfToken, err := util.GenerateRandomToken(32)
+		if err != nil {
+			log.Error(c.Request.Context(), err.Error())
+			r.Helper.SendBadRequest(c, err.Error(), fmt.Sprintf("file err : %s", err.Error()), traceID)
+			return
+		}
+
+		r.setSessionCookies(c, res.Token, res.RefreshToken, csrfToken)
+		res.Token, res.RefreshToken, res.CsrfToken = "", "", csrfToken
+	}
+
+	r.Helper.SendSuccess(c, "Success", res, traceID)
+}
+
+func (r Controller) setSessionCookies(c *gin.Context, token, refreshToken, csrfToken string) {
+	config := r.sessionCookieConfig()
+	tokenMaxAge := r.Config.GetInt("api_app_base.token_confidentiality_minute") * 60
+	refreshTokenMaxAge := r.Config.GetInt("api_app_base.refresh_token_confidentiality_minute") * 60
+
+	setSessionCookie(c, config, accessTokenCookie, token, "/", tokenMaxAge, true)
+	setSessionCookie(c, config, refreshTokenCookie, refreshToken, refreshTokenCookiePath, refreshTokenMaxAge, true)
+	// the front end read the csrf token, it live as long as the refresh token
+	setSessionCookie(c, config, csrfTokenCookie, csrfToken, "/", refreshTokenMaxAge, false)
+}
+
+// clearSessionCookies is called by the logout, it does nothing outside of the cookie mode
+func (r Controller) clearSessionCookies(c *gin.Context) {
+	if !r.isCookieSession(c) {
+		return
+	}
+
+	config := r.sessionCookieConfig()
+	setSessionCookie(c, config, accessTokenCookie, "", "/", -1, true)
+	setSessionCookie(c, config, refreshTokenCookie, "", refreshTokenCookiePath, -1, true)
+	setSessionCookie(c, config, csrfTokenCookie, "", "/", -1, false)
+}
+
+// cookieSessionToken return http.ErrNoCookie when the cookie mode is disabled or the cookie is not sent.
+// A state changing request must send the csrf cookie value in the X-CSRF-Token header
+func (r Controller) cookieSessionToken(c *gin.Context, cookieName string) (string, error) {
+	if !r.sessionCookieConfig().Enabled {
+		return "", http.ErrNoCookie
+	}
+
+	token, err := c.Cookie(cookieName)
+	if err != nil || token == "" {
+		return "", http.ErrNoCookie
+	}
+
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		csrfCookie, _ := c.Cookie(csrfTokenCookie)
+		csrfHeader := c.GetHeader(csrfTokenHeader)
+		if csrfCookie == "" || subtle.ConstantTimeCompare([]byte(csrfCookie), []byte(csrfHeader)) != 1 {
+			return "", CsrfTokenInvalid
+		}
+	}
+
+	c.Set(sessionCookieContextKey, true)
+	return token, nil
+}
+
+func setSessionCookie(c *gin.Context, config sessionCookieConfig, name, value, path string, maxAge int, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   config.Domain,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: httpOnly,
+		SameSite: config.SameSite,
+	})
+}
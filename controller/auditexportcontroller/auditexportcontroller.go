@@ -0,0 +1,76 @@
+package auditexportcontroller
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/exportauditeventv1"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Controller is a command, it write the audit log as json lines and verify the hash chain.
+// It exit with status 1 when the chain is broken
+type Controller struct {
+	DataSource *apibaseappgateway.GatewayApiBaseApp
+	// OutputPath is the exported file, the events are only verified when it is empty and written to stdout when it is -
+	OutputPath string
+
+	inputPort exportauditeventv1.Inport
+}
+
+// RegisterRouter only prepare the usecase, the command has no route
+func (r *Controller) RegisterRouter() {
+	r.inputPort = exportauditeventv1.NewUsecase(r.DataSource)
+}
+
+func (r *Controller) RunApplication() {
+	traceID := util.GenerateID()
+	ctx := log.Context(context.Background(), traceID)
+
+	output, closeOutput, err := r.openOutput()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "open output : ", err)
+		os.Exit(1)
+	}
+	writer := bufio.NewWriter(output)
+	encoder := json.NewEncoder(writer)
+
+	res, err := r.inputPort.Execute(ctx, entity.AuditExportReq{
+		Write: func(event entity.AuditEventData) error {
+			return encoder.Encode(event)
+		},
+	})
+
+	if flushErr := writer.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	closeOutput()
+
+	fmt.Fprintf(os.Stderr, "audit events : %d, last sequence : %d, last hash : %s\n", res.Count, res.LastSequence, res.LastHash)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "audit chain is valid")
+}
+
+func (r *Controller) openOutput() (io.Writer, func(), error) {
+	switch r.OutputPath {
+	case "":
+		return io.Discard, func() {}, nil
+	case "-":
+		return os.Stdout, func() {}, nil
+	}
+
+	file, err := os.Create(r.OutputPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, func() { file.Close() }, nil
+}
@@ -0,0 +1,137 @@
+package memberimportcontroller
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/lib/core/spreadsheet"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"backend_base_app/usecase/audit/v1/recordauditeventv1"
+	"backend_base_app/usecase/member/v1/importmemberv1"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Controller is a command, it create the members of a csv or xlsx file and write the report of every row
+// to stdout as json lines. It exit with status 1 when the file can not be read or a row failed
+type Controller struct {
+	DataSource *apibaseappgateway.GatewayApiBaseApp
+	Config     entity.MemberImportConfig
+	InputPath  string
+	// DryRun only validate the rows, nothing is written
+	DryRun bool
+
+	inputPort      importmemberv1.Inport
+	auditInputPort recordauditeventv1.Inport
+}
+
+// RegisterRouter only prepare the usecases, the command has no route
+func (r *Controller) RegisterRouter() {
+	r.inputPort = importmemberv1.NewUsecase(r.DataSource)
+	r.auditInputPort = recordauditeventv1.NewUsecase(r.DataSource)
+}
+
+func (r *Controller) RunApplication() {
+	traceID := util.GenerateID()
+	ctx := log.Context(context.Background(), traceID)
+
+	rows, err := r.readRows()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "read file : ", err)
+		os.Exit(1)
+	}
+
+	res, err := r.inputPort.Execute(ctx, entity.ImportMemberReq{
+		Rows:      rows,
+		DryRun:    r.DryRun,
+		BatchSize: r.Config.BatchSize,
+	})
+	r.recordAuditEvents(ctx, traceID, res, err)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	encoder := json.NewEncoder(writer)
+	for _, row := range res.Rows {
+		if err := encoder.Encode(row); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "members : %d, created : %d, skipped : %d, failed : %d, dry run : %t\n", res.Total, res.Created, res.Skipped, res.Failed, res.DryRun)
+	if res.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func (r *Controller) readRows() ([]entity.MemberImportRow, error) {
+	if r.InputPath == "" {
+		return nil, errors.New("file path must not empty")
+	}
+
+	file, err := os.Open(r.InputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, r.Config.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > r.Config.MaxSize {
+		return nil, entity.MemberImportFileTooLarge
+	}
+
+	return entity.ReadMemberImportRows(spreadsheet.DetectFormat(r.InputPath, data), data, r.Config)
+}
+
+// recordAuditEvents use the system actor, like the api every created member is in the audit log.
+// Its error is only logged, the members are already created
+func (r *Controller) recordAuditEvents(ctx context.Context, traceID string, res *entity.ImportMemberRes, err error) {
+	events := []entity.AuditEventReq{{
+		Action:    entity.AuditActionMemberImport,
+		ActorType: entity.AuditActorSystem,
+		TraceId:   traceID,
+		Metadata:  map[string]string{"file_name": filepath.Base(r.InputPath), "dry_run": strconv.FormatBool(r.DryRun)},
+		Err:       err,
+	}}
+	if err == nil {
+		events[0].Metadata["created"] = strconv.Itoa(res.Created)
+		events[0].Metadata["skipped"] = strconv.Itoa(res.Skipped)
+		events[0].Metadata["failed"] = strconv.Itoa(res.Failed)
+
+		for _, row := range res.Rows {
+			if res.DryRun || row.Status != entity.MemberImportCreated {
+				continue
+			}
+			events = append(events, entity.AuditEventReq{
+				Action:         entity.AuditActionMemberCreate,
+				TargetMemberId: row.MemberId,
+				ActorType:      entity.AuditActorSystem,
+				TraceId:        traceID,
+				Metadata:       map[string]string{"username": row.Username, "source": "import"},
+			})
+		}
+	}
+
+	for _, event := range events {
+		if _, err := r.auditInputPort.Execute(ctx, event); err != nil {
+			log.Error(ctx, err.Error())
+		}
+	}
+}
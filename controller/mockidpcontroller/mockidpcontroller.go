@@ -0,0 +1,153 @@
+package mockidpcontroller
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+
+	"backend_base_app/shared/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const authorizationCodeLifetime = time.Minute
+
+func (r *Controller) issuer() string {
+	return r.Config.GetString("mock_idp.issuer")
+}
+
+func MockIdpDiscovery(r *Controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"issuer":                                r.issuer(),
+			"authorization_endpoint":                r.issuer() + "/authorize",
+			"token_endpoint":                        r.issuer() + "/token",
+			"jwks_uri":                              r.issuer() + "/jwks",
+			"response_types_supported":              []string{"code"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{r.KeySet.SigningKey().Method.Alg()},
+			"code_challenge_methods_supported":      []string{"S256"},
+			"scopes_supported":                      []string{"openid", "email", "profile"},
+		})
+	}
+}
+
+func MockIdpJwks(r *Controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, r.KeySet.JWKS())
+	}
+}
+
+// MockIdpAuthorize sign in at once and redirect back with the code,
+// login_hint replace the configured email to try another member
+func MockIdpAuthorize(r *Controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query("client_id") != r.Config.GetString("mock_idp.client_id") {
+			c.String(http.StatusBadRequest, "unknown client_id")
+			return
+		}
+		if c.Query("code_challenge_method") != "S256" || c.Query("code_challenge") == "" {
+			c.String(http.StatusBadRequest, "S256 code_challenge is required")
+			return
+		}
+
+		redirectUri, err := url.Parse(c.Query("redirect_uri"))
+		if err != nil || redirectUri.Scheme == "" {
+			c.String(http.StatusBadRequest, "invalid redirect_uri")
+			return
+		}
+
+		subject := r.Config.GetString("mock_idp.subject")
+		email := r.Config.GetString("mock_idp.email")
+		if loginHint := c.Query("login_hint"); loginHint != "" {
+			subject = "mock-" + loginHint
+			email = loginHint
+		}
+
+		code, err := util.GenerateRandomToken(32)
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		r.mu.Lock()
+		r.codes[code] = authorizationCode{
+			ClientId:      c.Query("client_id"),
+			RedirectUri:   c.Query("redirect_uri"),
+			Nonce:         c.Query("nonce"),
+			CodeChallenge: c.Query("code_challenge"),
+			Subject:       subject,
+			Email:         email,
+			ExpiredAt:     time.Now().Add(authorizationCodeLifetime),
+		}
+		r.mu.Unlock()
+
+		query := redirectUri.Query()
+		query.Set("code", code)
+		query.Set("state", c.Query("state"))
+		redirectUri.RawQuery = query.Encode()
+
+		c.Redirect(http.StatusFound, redirectUri.String())
+	}
+}
+
+// MockIdpToken redeem a code once, after checking the client and the PKCE verifier
+func MockIdpToken(r *Controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientId, clientSecret, ok := c.Request.BasicAuth()
+		if !ok {
+			clientId = c.PostForm("client_id")
+			clientSecret = c.PostForm("client_secret")
+		}
+		if clientId != r.Config.GetString("mock_idp.client_id") || clientSecret != r.Config.GetString("mock_idp.client_secret") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+			return
+		}
+
+		r.mu.Lock()
+		code, exist := r.codes[c.PostForm("code")]
+		delete(r.codes, c.PostForm("code"))
+		r.mu.Unlock()
+
+		if c.PostForm("grant_type") != "authorization_code" || !exist || time.Now().After(code.ExpiredAt) ||
+			code.ClientId != clientId || code.RedirectUri != c.PostForm("redirect_uri") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant"})
+			return
+		}
+
+		sum := sha256.Sum256([]byte(c.PostForm("code_verifier")))
+		challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+		if subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) != 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "code_verifier does not match"})
+			return
+		}
+
+		now := time.Now()
+		idToken, err := r.Helper.CreateJwtTokenWithKey(r.KeySet.SigningKey(), jwt.MapClaims{
+			"iss":            r.issuer(),
+			"sub":            code.Subject,
+			"aud":            clientId,
+			"iat":            now.Unix(),
+			"exp":            now.Add(5 * time.Minute).Unix(),
+			"nonce":          code.Nonce,
+			"email":          code.Email,
+			"email_verified": r.Config.GetBool("mock_idp.email_verified"),
+			"name":           r.Config.GetString("mock_idp.name"),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"access_token": util.GenerateUuidWithoutDash(),
+			"token_type":   "Bearer",
+			"expires_in":   300,
+			"id_token":     idToken,
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package mockidpcontroller
+
+import (
+	cfg "backend_base_app/config/env"
+	"backend_base_app/lib/core/jwtkey"
+	"backend_base_app/shared/helper"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Controller is a local OpenID provider for development, it sign in the configured identity
+// without asking anything. It must never be deployed
+type Controller struct {
+	Router gin.IRouter
+	Helper helper.HTTPHelper
+	Config cfg.Config
+	KeySet *jwtkey.KeySet
+
+	mu    sync.Mutex
+	codes map[string]authorizationCode
+}
+
+type authorizationCode struct {
+	ClientId      string
+	RedirectUri   string
+	Nonce         string
+	CodeChallenge string
+	Subject       string
+	Email         string
+	ExpiredAt     time.Time
+}
+
+func (r *Controller) RegisterRouter() {
+	r.codes = map[string]authorizationCode{}
+
+	r.Router.GET("/.well-known/openid-configuration", MockIdpDiscovery(r))
+	r.Router.GET("/jwks", MockIdpJwks(r))
+	r.Router.GET("/authorize", MockIdpAuthorize(r))
+	r.Router.POST("/token", MockIdpToken(r))
+}
@@ -0,0 +1,143 @@
+package entity
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionApiKey string = "api_key"
+)
+
+// ApiKeyPrefix start every key, so a leaked key is easy to recognize
+const ApiKeyPrefix = "bak"
+
+// ApiKeyData is a key of a machine client. The key is <prefix>.<secret>, only the prefix is kept in clear
+// to find the key and to show it in the list, the whole key is hashed
+type ApiKeyData struct {
+	ID         string     `json:"id" bson:"id"`
+	Name       string     `json:"name" bson:"name"`
+	Prefix     string     `json:"prefix" bson:"prefix"`
+	KeyHash    string     `json:"-" bson:"key_hash"`
+	Scopes     []string   `json:"scopes" bson:"scopes"`
+	CreatedBy  string     `json:"created_by" bson:"created_by"`
+	ExpiredAt  *time.Time `json:"expired_at" bson:"expired_at"`
+	LastUsedAt *time.Time `json:"last_used_at" bson:"last_used_at"`
+	IsRevoked  bool       `json:"is_revoked" bson:"is_revoked"`
+	RevokedAt  *time.Time `json:"revoked_at" bson:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+type ApiKeyDataFind struct {
+	Name           string `form:"name"`
+	IncludeRevoked bool   `form:"include_revoked"`
+}
+
+type CreateApiKeyReq struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiredAt is optional, the key never expires without it
+	ExpiredAt *time.Time `json:"expired_at"`
+
+	// filled from the token, not from the body
+	CreatedBy string `json:"-"`
+	// GrantedPermissions are the permissions of the member creating the key, a key can not get more
+	GrantedPermissions []string `json:"-"`
+}
+
+// CreateApiKeyRes is the only response holding the plain key
+type CreateApiKeyRes struct {
+	ApiKeyData
+	Key string `json:"key"`
+}
+
+func (r CreateApiKeyReq) Validate() error {
+	if len(strings.TrimSpace(r.Name)) == 0 {
+		return ApiKeyNameMustNotEmpty
+	}
+	if len(r.Scopes) == 0 {
+		return ApiKeyScopeMustNotEmpty
+	}
+	if r.ExpiredAt != nil && !r.ExpiredAt.After(time.Now()) {
+		return ApiKeyExpiredAtInvalid
+	}
+
+	granted := RoleData{Permissions: r.GrantedPermissions}
+	for _, scope := range r.Scopes {
+		if !granted.HasPermission(scope) {
+			return ApiKeyScopeNotGranted.Var(scope)
+		}
+	}
+
+	return nil
+}
+
+// NewApiKeyData return the key data and the plain key, which is only shown once
+func NewApiKeyData(req CreateApiKeyReq) (*ApiKeyData, string, error) {
+	prefixToken, err := util.GenerateRandomDigits(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix := fmt.Sprintf("%s_%s", ApiKeyPrefix, prefixToken)
+	key := fmt.Sprintf("%s.%s", prefix, secret)
+
+	return &ApiKeyData{
+		ID:        util.GenerateUuidWithoutDash(),
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    prefix,
+		KeyHash:   util.HashToken(key),
+		Scopes:    req.Scopes,
+		CreatedBy: req.CreatedBy,
+		ExpiredAt: req.ExpiredAt,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, key, nil
+}
+
+// ApiKeyPrefixOf return the prefix part of a key
+func ApiKeyPrefixOf(key string) (string, bool) {
+	prefix, _, found := strings.Cut(key, ".")
+	if !found || !strings.HasPrefix(prefix, ApiKeyPrefix+"_") {
+		return "", false
+	}
+	return prefix, true
+}
+
+func (r ApiKeyData) MatchKey(key string) bool {
+	return subtle.ConstantTimeCompare([]byte(r.KeyHash), []byte(util.HashToken(key))) == 1
+}
+
+// CheckActive return the reason a matching key can not be used
+func (r ApiKeyData) CheckActive(now time.Time) error {
+	if r.IsRevoked {
+		return ApiKeyRevoked
+	}
+	if r.ExpiredAt != nil && !now.Before(*r.ExpiredAt) {
+		return ApiKeyExpired
+	}
+	return nil
+}
+
+func (r ApiKeyData) HasScope(permission string) bool {
+	return RoleData{Permissions: r.Scopes}.HasPermission(permission)
+}
+
+const ApiKeyNameMustNotEmpty domerror.ErrorType = "ER1000 api key name must not empty"
+const ApiKeyScopeMustNotEmpty domerror.ErrorType = "ER1000 api key scopes must not empty"
+const ApiKeyExpiredAtInvalid domerror.ErrorType = "ER1002 api key expired_at must be in the future"
+const ApiKeyScopeNotGranted domerror.ErrorType = "ER1009 scope %s is not granted to your role"
+const ApiKeyNotFound domerror.ErrorType = "ER1001 api key %s is not found"
+const ApiKeyInvalid domerror.ErrorType = "ER1015 api key is invalid"
+const ApiKeyRevoked domerror.ErrorType = "ER1015 api key has been revoked"
+const ApiKeyExpired domerror.ErrorType = "ER1015 api key has expired"
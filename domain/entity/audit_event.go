@@ -0,0 +1,275 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionAuditEvent string = "audit_events"
+)
+
+// Actions of the audit log
+const (
+	AuditActionLogin               = "auth.login"
+	AuditActionLoginTwoFactor      = "auth.login_2fa"
+	AuditActionLoginOidc           = "auth.login_oidc"
+	AuditActionLoginPasswordless   = "auth.login_passwordless"
+	AuditActionPasswordlessStart   = "auth.passwordless_start"
+	AuditActionRefresh             = "auth.refresh"
+	AuditActionLogout              = "auth.logout"
+	AuditActionLogoutAll           = "auth.logout_all"
+	AuditActionSessionRevoke       = "auth.session_revoke"
+	AuditActionPasswordForgot      = "auth.password_forgot"
+	AuditActionPasswordReset       = "auth.password_reset"
+	AuditActionPasswordChange      = "auth.password_change"
+	AuditActionVerificationConfirm = "auth.verification_confirm"
+	AuditActionTwoFactorEnable     = "auth.2fa_enable"
+	AuditActionTwoFactorDisable    = "auth.2fa_disable"
+	AuditActionImpersonate         = "auth.impersonate"
+	AuditActionReauthenticate      = "auth.reauthenticate"
+	AuditActionMemberCreate        = "member.create"
+	AuditActionMemberUpdate        = "member.update"
+	AuditActionMemberDelete        = "member.delete"
+	AuditActionMemberRestore       = "member.restore"
+	AuditActionMemberPurge         = "member.purge"
+	AuditActionMemberPhotoUpload   = "member.photo_upload"
+	AuditActionMemberImport        = "member.import"
+	AuditActionMemberRoleAssign    = "member.role_assign"
+	AuditActionMemberSuspend       = "member.suspend"
+	AuditActionMemberUnsuspend     = "member.unsuspend"
+	AuditActionRoleCreate          = "role.create"
+	AuditActionRoleUpdate          = "role.update"
+	AuditActionRoleDelete          = "role.delete"
+	AuditActionLockoutDelete       = "lockout.delete"
+	AuditActionApiKeyCreate        = "api_key.create"
+	AuditActionApiKeyRevoke        = "api_key.revoke"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+const (
+	AuditActorAnonymous = "anonymous"
+	AuditActorMember    = "member"
+	AuditActorApiKey    = "api_key"
+	// AuditActorSystem is the service itself, like the suspension sweep
+	AuditActorSystem = "system"
+)
+
+// AuditChange is a field changed by the action, the values are json so the hash stay the same
+// after the event is read back
+type AuditChange struct {
+	Field  string `json:"field" bson:"field"`
+	Before string `json:"before" bson:"before"`
+	After  string `json:"after" bson:"after"`
+}
+
+// AuditEventData is never updated. Hash cover every other field and the hash of the previous event,
+// so a changed or removed event break the chain
+type AuditEventData struct {
+	ID             string            `json:"id" bson:"id"`
+	Sequence       int64             `json:"sequence" bson:"sequence"`
+	Action         string            `json:"action" bson:"action"`
+	Outcome        string            `json:"outcome" bson:"outcome"`
+	Reason         string            `json:"reason" bson:"reason"`
+	ActorType      string            `json:"actor_type" bson:"actor_type"`
+	ActorId        string            `json:"actor_id" bson:"actor_id"`
+	TargetMemberId string            `json:"target_member_id" bson:"target_member_id"`
+	IpAddress      string            `json:"ip_address" bson:"ip_address"`
+	UserAgent      string            `json:"user_agent" bson:"user_agent"`
+	TraceId        string            `json:"trace_id" bson:"trace_id"`
+	Metadata       map[string]string `json:"metadata" bson:"metadata"`
+	Changes        []AuditChange     `json:"changes" bson:"changes"`
+	CreatedAt      time.Time         `json:"created_at" bson:"created_at"`
+	PrevHash       string            `json:"prev_hash" bson:"prev_hash"`
+	Hash           string            `json:"hash" bson:"hash"`
+}
+
+type AuditEventDataFind struct {
+	Action         string     `form:"action"`
+	Outcome        string     `form:"outcome"`
+	ActorId        string     `form:"actor_id"`
+	TargetMemberId string     `form:"target_member_id"`
+	IpAddress      string     `form:"ip_address"`
+	TraceId        string     `form:"trace_id"`
+	From           *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To             *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// AuditEventReq is filled by the controller, the actor and the client come from the request
+type AuditEventReq struct {
+	Action         string
+	TargetMemberId string
+	ActorType      string
+	ActorId        string
+	IpAddress      string
+	UserAgent      string
+	TraceId        string
+	Metadata       map[string]string
+	Changes        []AuditChange
+	// Err is the result of the action, the outcome is a failure when it is set
+	Err error
+}
+
+// AuditExportReq call Write with every event in sequence order
+type AuditExportReq struct {
+	Write func(event AuditEventData) error
+}
+
+type AuditExportRes struct {
+	Count        int64  `json:"count"`
+	LastSequence int64  `json:"last_sequence"`
+	LastHash     string `json:"last_hash"`
+}
+
+func NewAuditEventData(req AuditEventReq) AuditEventData {
+	obj := AuditEventData{
+		ID:             util.GenerateUuidWithoutDash(),
+		Action:         req.Action,
+		Outcome:        AuditOutcomeSuccess,
+		ActorType:      req.ActorType,
+		ActorId:        req.ActorId,
+		TargetMemberId: req.TargetMemberId,
+		IpAddress:      req.IpAddress,
+		UserAgent:      req.UserAgent,
+		TraceId:        req.TraceId,
+		Metadata:       req.Metadata,
+		Changes:        req.Changes,
+		// mongo keep milliseconds, the hash must be computed on the stored value
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	if obj.ActorType == "" {
+		obj.ActorType = AuditActorAnonymous
+	}
+	if req.Err != nil {
+		obj.Outcome = AuditOutcomeFailure
+		obj.Reason = req.Err.Error()
+	}
+
+	return obj
+}
+
+// Chain set the sequence and the hashes after the previous event, previous is nil for the first event
+func (r *AuditEventData) Chain(previous *AuditEventData) {
+	r.Sequence = 1
+	r.PrevHash = ""
+	if previous != nil {
+		r.Sequence = previous.Sequence + 1
+		r.PrevHash = previous.Hash
+	}
+	r.Hash = r.ComputeHash()
+}
+
+// ComputeHash is the sha256 of the event without its own hash
+func (r AuditEventData) ComputeHash() string {
+	metadata := r.Metadata
+	if len(metadata) == 0 {
+		metadata = nil
+	}
+	changes := r.Changes
+	if len(changes) == 0 {
+		changes = nil
+	}
+
+	content, _ := json.Marshal(struct {
+		ID             string
+		Sequence       int64
+		Action         string
+		Outcome        string
+		Reason         string
+		ActorType      string
+		ActorId        string
+		TargetMemberId string
+		IpAddress      string
+		UserAgent      string
+		TraceId        string
+		Metadata       map[string]string
+		Changes        []AuditChange
+		CreatedAt      string
+		PrevHash       string
+	}{
+		ID:             r.ID,
+		Sequence:       r.Sequence,
+		Action:         r.Action,
+		Outcome:        r.Outcome,
+		Reason:         r.Reason,
+		ActorType:      r.ActorType,
+		ActorId:        r.ActorId,
+		TargetMemberId: r.TargetMemberId,
+		IpAddress:      r.IpAddress,
+		UserAgent:      r.UserAgent,
+		TraceId:        r.TraceId,
+		Metadata:       metadata,
+		Changes:        changes,
+		CreatedAt:      r.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:       r.PrevHash,
+	})
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyChain return AuditChainBroken when the event does not follow previous or its hash does not match
+func (r AuditEventData) VerifyChain(previous *AuditEventData) error {
+	expectedSequence, expectedPrevHash := int64(1), ""
+	if previous != nil {
+		expectedSequence, expectedPrevHash = previous.Sequence+1, previous.Hash
+	}
+
+	if r.Sequence != expectedSequence {
+		return AuditChainBroken.Var(expectedSequence, fmt.Sprintf("found sequence %d", r.Sequence))
+	}
+	if r.PrevHash != expectedPrevHash {
+		return AuditChainBroken.Var(r.Sequence, "previous hash does not match")
+	}
+	if r.Hash != r.ComputeHash() {
+		return AuditChainBroken.Var(r.Sequence, "hash does not match the content")
+	}
+
+	return nil
+}
+
+// NewAuditChanges compare the json fields of before and after, a nil before list every field of after
+func NewAuditChanges(before, after interface{}) []AuditChange {
+	beforeFields := map[string]json.RawMessage{}
+	afterFields := map[string]json.RawMessage{}
+	if before != nil {
+		_ = json.Unmarshal([]byte(util.StructToJson(before)), &beforeFields)
+	}
+	if after != nil {
+		_ = json.Unmarshal([]byte(util.StructToJson(after)), &afterFields)
+	}
+
+	fields := []string{}
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+	for field := range afterFields {
+		if _, exist := beforeFields[field]; !exist {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []AuditChange{}
+	for _, field := range fields {
+		beforeValue, afterValue := string(beforeFields[field]), string(afterFields[field])
+		if beforeValue != afterValue {
+			changes = append(changes, AuditChange{Field: field, Before: beforeValue, After: afterValue})
+		}
+	}
+
+	return changes
+}
+
+const AuditChainBroken domerror.ErrorType = "ER1018 audit chain is broken at sequence %d : %s"
@@ -37,6 +37,8 @@ type MyError struct {
 type AuthRefreshToken struct {
 	Id       string `json:"id" bson:"id"`
 	DeviceId string `json:"id_device" bson:"id_device"`
+	TokenId  string `json:"id_token" bson:"id_token"`
+	FamilyId string `json:"id_family" bson:"id_family"`
 }
 
 // Implement the Error method for MyError
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+type ImpersonateMemberReq struct {
+	MemberId string `json:"-"`
+	Reason   string `json:"reason"`
+
+	// filled from the token, not from the body
+	ActorId    string   `json:"-"`
+	ActorRoles []string `json:"-"`
+}
+
+// ImpersonateMemberRes hold an access token of the member carrying the admin in its act claim,
+// there is no refresh token, the admin ask a new one when it expires
+type ImpersonateMemberRes struct {
+	Token          string          `json:"token"`
+	ExpiredAt      time.Time       `json:"expired_at"`
+	ImpersonatedBy string          `json:"impersonated_by"`
+	Member         MemberDataShown `json:"member"`
+}
+
+// Validate check the role first, so an attempt without it is refused whatever the body
+func (r ImpersonateMemberReq) Validate() error {
+	if !slices.Contains(r.ActorRoles, RoleSuperadmin) {
+		return ImpersonationRequireSuperadmin
+	}
+	if len(strings.TrimSpace(r.Reason)) == 0 {
+		return ImpersonationReasonMustNotEmpty
+	}
+	if r.MemberId == r.ActorId {
+		return ImpersonationNotAllowed.Var("yourself")
+	}
+	return nil
+}
+
+// CheckImpersonable return the reason the member can not be impersonated
+func (r MemberDataShown) CheckImpersonable() error {
+	if r.IsSuspend {
+		return ImpersonationNotAllowed.Var("a suspended member")
+	}
+	// a superadmin could be used to hide the action of another superadmin
+	if slices.Contains(r.GetRoles(), RoleSuperadmin) {
+		return ImpersonationNotAllowed.Var("a superadmin")
+	}
+	return nil
+}
+
+const ImpersonationReasonMustNotEmpty domerror.ErrorType = "ER1000 reason must not empty"
+const ImpersonationRequireSuperadmin domerror.ErrorType = "ER1009 only a superadmin can impersonate a member"
+const ImpersonationNotAllowed domerror.ErrorType = "ER1019 impersonating %s is not allowed"
@@ -0,0 +1,164 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+const (
+	CollectionLoginAttempt string = "login_attempt"
+)
+
+const (
+	LoginAttemptKindUsername string = "username"
+	LoginAttemptKindIp       string = "ip"
+)
+
+// LoginAttemptData count the failed login of a single username or client ip.
+// LockedUntil is the time the next attempt is allowed, it is set by the backoff and by the lockout.
+// The document is removed once ExpiredAt has passed, which reset the counter
+type LoginAttemptData struct {
+	ID           string     `json:"id" bson:"id"`
+	Kind         string     `json:"kind" bson:"kind"`
+	Key          string     `json:"key" bson:"key"`
+	FailedCount  int        `json:"failed_count" bson:"failed_count"`
+	IsLockedOut  bool       `json:"is_locked_out" bson:"is_locked_out"`
+	LockedUntil  *time.Time `json:"locked_until" bson:"locked_until"`
+	LastFailedAt time.Time  `json:"last_failed_at" bson:"last_failed_at"`
+	CreatedAt    time.Time  `json:"created_at" bson:"created_at"`
+	ExpiredAt    time.Time  `json:"expired_at" bson:"expired_at"`
+}
+
+type LoginAttemptDataFind struct {
+	Kind       string `form:"kind"`
+	Key        string `form:"key"`
+	OnlyLocked bool   `form:"only_locked"`
+}
+
+// LoginProtectionConfig is read from login_protection config
+type LoginProtectionConfig struct {
+	Enabled bool
+	// FreeAttempts is the number of failed attempts allowed before the backoff start
+	FreeAttempts int
+	// BaseDelay is doubled on every failed attempt after FreeAttempts, up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Window is the time without failed attempt needed to reset the counter
+	Window                   time.Duration
+	LockoutDuration          time.Duration
+	UsernameLockoutThreshold int
+	IpLockoutThreshold       int
+}
+
+// LoginLockedError is returned while the username or the client ip has to wait before the next attempt
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e LoginLockedError) Error() string {
+	return LoginIsLocked.Var(e.RetryAfterSecond()).Error()
+}
+
+func (e LoginLockedError) Code() string {
+	return LoginIsLocked.Code()
+}
+
+// RetryAfterSecond is rounded up, so the client never retries too early
+func (e LoginLockedError) RetryAfterSecond() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+func LoginAttemptId(kind, key string) string {
+	return fmt.Sprintf("%s:%s", kind, key)
+}
+
+// LoginAttemptIds return the counters checked on a login request
+func LoginAttemptIds(username, ipAddress string) []string {
+	ids := []string{LoginAttemptId(LoginAttemptKindUsername, username)}
+	if ipAddress != "" {
+		ids = append(ids, LoginAttemptId(LoginAttemptKindIp, ipAddress))
+	}
+	return ids
+}
+
+// LoginAttemptKeys return the counters incremented on a failed login, by kind
+func LoginAttemptKeys(username, ipAddress string) map[string]string {
+	keys := map[string]string{LoginAttemptKindUsername: username}
+	if ipAddress != "" {
+		keys[LoginAttemptKindIp] = ipAddress
+	}
+	return keys
+}
+
+// CheckLoginAttempts return LoginLockedError with the longest wait of the counters
+func CheckLoginAttempts(attempts []*LoginAttemptData, now time.Time) error {
+	lockedErr := LoginLockedError{}
+	for _, attempt := range attempts {
+		if retryAfter := attempt.RetryAfter(now); retryAfter > lockedErr.RetryAfter {
+			lockedErr.RetryAfter = retryAfter
+		}
+	}
+	if lockedErr.RetryAfter > 0 {
+		return lockedErr
+	}
+	return nil
+}
+
+func NewLoginAttemptData(kind, key string, expiredAt time.Time) LoginAttemptData {
+	return LoginAttemptData{
+		ID:        LoginAttemptId(kind, key),
+		Kind:      kind,
+		Key:       key,
+		CreatedAt: time.Now(),
+		ExpiredAt: expiredAt,
+	}
+}
+
+// RetryAfter return how long the next attempt has to wait, zero when it is allowed
+func (r LoginAttemptData) RetryAfter(now time.Time) time.Duration {
+	if r.LockedUntil == nil || !now.Before(*r.LockedUntil) || !now.Before(r.ExpiredAt) {
+		return 0
+	}
+	return r.LockedUntil.Sub(now)
+}
+
+// ApplyFailure set the backoff or the lockout after FailedCount has been incremented
+func (r *LoginAttemptData) ApplyFailure(config LoginProtectionConfig, now time.Time) {
+	threshold := config.UsernameLockoutThreshold
+	if r.Kind == LoginAttemptKindIp {
+		threshold = config.IpLockoutThreshold
+	}
+
+	r.LockedUntil = nil
+	r.IsLockedOut = false
+	r.ExpiredAt = now.Add(config.Window)
+
+	switch {
+	case threshold > 0 && r.FailedCount >= threshold:
+		lockedUntil := now.Add(config.LockoutDuration)
+		r.LockedUntil = &lockedUntil
+		r.IsLockedOut = true
+		// the counter start again once the lockout is over
+		r.ExpiredAt = lockedUntil
+
+	case r.FailedCount > config.FreeAttempts && config.BaseDelay > 0:
+		delay := config.MaxDelay
+		if shift := r.FailedCount - config.FreeAttempts - 1; shift < 16 {
+			delay = config.BaseDelay * time.Duration(1<<shift)
+		}
+		if config.MaxDelay > 0 && delay > config.MaxDelay {
+			delay = config.MaxDelay
+		}
+		lockedUntil := now.Add(delay)
+		r.LockedUntil = &lockedUntil
+		if lockedUntil.After(r.ExpiredAt) {
+			r.ExpiredAt = lockedUntil
+		}
+	}
+}
+
+const LoginIsLocked domerror.ErrorType = "ER1011 too many failed login attempts, retry after %d seconds"
+const LoginAttemptNotFound domerror.ErrorType = "ER1001 login attempt %s is not found"
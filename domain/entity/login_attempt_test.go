@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginAttemptApplyFailure(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := LoginProtectionConfig{
+		Enabled:                  true,
+		FreeAttempts:             3,
+		BaseDelay:                time.Second,
+		MaxDelay:                 time.Minute,
+		Window:                   15 * time.Minute,
+		LockoutDuration:          time.Hour,
+		UsernameLockoutThreshold: 10,
+		IpLockoutThreshold:       50,
+	}
+
+	tests := []struct {
+		name        string
+		kind        string
+		failedCount int
+		config      LoginProtectionConfig
+		wantDelay   time.Duration
+		wantLocked  bool
+		wantExpired time.Duration
+	}{
+		{"free attempt", LoginAttemptKindUsername, 3, config, 0, false, 15 * time.Minute},
+		{"first backoff", LoginAttemptKindUsername, 4, config, time.Second, false, 15 * time.Minute},
+		{"backoff doubles", LoginAttemptKindUsername, 5, config, 2 * time.Second, false, 15 * time.Minute},
+		{"backoff doubles again", LoginAttemptKindUsername, 7, config, 8 * time.Second, false, 15 * time.Minute},
+		{"backoff before the lockout", LoginAttemptKindUsername, 9, config, 32 * time.Second, false, 15 * time.Minute},
+		{"backoff capped by max delay", LoginAttemptKindIp, 20, config, time.Minute, false, 15 * time.Minute},
+		{"username lockout", LoginAttemptKindUsername, 10, config, time.Hour, true, time.Hour},
+		{"ip has its own threshold", LoginAttemptKindIp, 10, config, time.Minute, false, 15 * time.Minute},
+		{"ip lockout", LoginAttemptKindIp, 50, config, time.Hour, true, time.Hour},
+		{"huge count does not overflow", LoginAttemptKindIp, 40, config, time.Minute, false, 15 * time.Minute},
+		{"lockout disabled", LoginAttemptKindUsername, 100, LoginProtectionConfig{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute, Window: 15 * time.Minute}, time.Minute, false, 15 * time.Minute},
+		{"backoff disabled", LoginAttemptKindUsername, 5, LoginProtectionConfig{FreeAttempts: 3, Window: 15 * time.Minute}, 0, false, 15 * time.Minute},
+		{"delay longer than the window", LoginAttemptKindUsername, 5, LoginProtectionConfig{FreeAttempts: 3, BaseDelay: 10 * time.Minute, Window: 15 * time.Minute}, 20 * time.Minute, false, 20 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempt := NewLoginAttemptData(tt.kind, "key", now)
+			attempt.FailedCount = tt.failedCount
+			attempt.ApplyFailure(tt.config, now)
+
+			if got := attempt.RetryAfter(now); got != tt.wantDelay {
+				t.Errorf("RetryAfter() = %v, want %v", got, tt.wantDelay)
+			}
+			if attempt.IsLockedOut != tt.wantLocked {
+				t.Errorf("IsLockedOut = %v, want %v", attempt.IsLockedOut, tt.wantLocked)
+			}
+			if got := attempt.ExpiredAt.Sub(now); got != tt.wantExpired {
+				t.Errorf("ExpiredAt = now + %v, want now + %v", got, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestLoginAttemptApplyFailureResetPreviousLock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lockedUntil := now.Add(time.Hour)
+	attempt := LoginAttemptData{Kind: LoginAttemptKindUsername, FailedCount: 1, IsLockedOut: true, LockedUntil: &lockedUntil}
+
+	attempt.ApplyFailure(LoginProtectionConfig{FreeAttempts: 3, Window: time.Minute}, now)
+
+	if attempt.LockedUntil != nil || attempt.IsLockedOut {
+		t.Errorf("ApplyFailure() kept the previous lock : %+v", attempt)
+	}
+}
+
+func TestCheckLoginAttempts(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		attempts  []*LoginAttemptData
+		wantRetry int
+	}{
+		{"no attempt", nil, 0},
+		{"not locked", []*LoginAttemptData{{ExpiredAt: now.Add(time.Hour)}}, 0},
+		{"lock is over", []*LoginAttemptData{{LockedUntil: at(-time.Second), ExpiredAt: now.Add(time.Hour)}}, 0},
+		{"counter expired", []*LoginAttemptData{{LockedUntil: at(time.Minute), ExpiredAt: now}}, 0},
+		{"rounded up", []*LoginAttemptData{{LockedUntil: at(1500 * time.Millisecond), ExpiredAt: now.Add(time.Hour)}}, 2},
+		{"longest wait", []*LoginAttemptData{
+			{LockedUntil: at(5 * time.Second), ExpiredAt: now.Add(time.Hour)},
+			{LockedUntil: at(30 * time.Second), ExpiredAt: now.Add(time.Hour)},
+		}, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckLoginAttempts(tt.attempts, now)
+			if tt.wantRetry == 0 {
+				if err != nil {
+					t.Errorf("CheckLoginAttempts() = %v, want nil", err)
+				}
+				return
+			}
+			lockedErr, ok := err.(LoginLockedError)
+			if !ok {
+				t.Fatalf("CheckLoginAttempts() = %v, want LoginLockedError", err)
+			}
+			if lockedErr.RetryAfterSecond() != tt.wantRetry {
+				t.Errorf("RetryAfterSecond() = %d, want %d", lockedErr.RetryAfterSecond(), tt.wantRetry)
+			}
+		})
+	}
+}
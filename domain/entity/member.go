@@ -40,11 +40,33 @@ type MemberData struct {
 	TokenBroadcast string       `json:"token_broadcast" bson:"token_broadcast" form:"token_broadcast"`
 	LastLogin      time.Time    `json:"last_login" bson:"last_login" form:"last_login"`
 	DeviceId       string       `json:"id_device" bson:"id_device" form:"id_device"`
+	Roles          []string     `json:"roles" bson:"roles" form:"roles"`
 
 	// Info
 	PhoneNumber string `json:"phone_number" bson:"phone_number"`
 	Email       string `json:"email" bson:"email"`
 	MemberPhoto string `json:"photo_member" bson:"photo_member"`
+	// MemberPhotoThumbnails is the url of the thumbnails by size, MemberPhotoKeys are the stored objects
+	// of the uploaded photo, they are removed when the photo is replaced
+	MemberPhotoThumbnails map[string]string `json:"photo_member_thumbnails" bson:"photo_member_thumbnails"`
+	MemberPhotoKeys       []string          `json:"photo_member_keys" bson:"photo_member_keys"`
+
+	// Verification
+	EmailVerifiedAt *time.Time `json:"email_verified_at" bson:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at" bson:"phone_verified_at"`
+
+	// Suspension
+	SuspendReason  string     `json:"suspend_reason" bson:"suspend_reason"`
+	SuspendedBy    string     `json:"suspended_by" bson:"suspended_by"`
+	SuspendedAt    *time.Time `json:"suspended_at" bson:"suspended_at"`
+	SuspendedUntil *time.Time `json:"suspended_until" bson:"suspended_until"`
+
+	// Deletion, a deleted member is kept until it is purged
+	DeletedAt *time.Time `json:"deleted_at" bson:"deleted_at"`
+	DeletedBy string     `json:"deleted_by" bson:"deleted_by"`
+
+	// CredentialVersion is increased on every password change, older tokens are rejected
+	CredentialVersion int `json:"credential_version" bson:"credential_version"`
 }
 
 type CreateMemberData struct {
@@ -64,6 +86,15 @@ type MemberReqAuth struct {
 	Password       string `json:"password" form:"password"`
 	TokenBroadcast string `json:"token_broadcast" form:"token_broadcast"`
 	DeviceId       string `json:"id_device" form:"id_device"`
+	Platform       string `json:"platform" form:"platform"`
+
+	// filled from the request, not from the body
+	UserAgent string `json:"-" form:"-"`
+	IpAddress string `json:"-" form:"-"`
+
+	LoginProtection LoginProtectionConfig `json:"-" form:"-"`
+	// RequiredVerification is the verification channel required to login by member type
+	RequiredVerification map[string]string `json:"-" form:"-"`
 }
 
 type MemberResAuth struct {
@@ -78,14 +109,21 @@ type MemberResAuth struct {
 	LastLogin      time.Time `json:"last_login"`
 	TokenBroadcast string    `json:"token_broadcast"`
 	DeviceId       string    `json:"id_device"`
+	Roles          []string  `json:"roles"`
 
 	// Info
 	PhoneNumber string `json:"phone_number"`
 	Email       string `json:"email"`
 	MemberPhoto string `json:"photo_member"`
 
+	// Verification
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
+
 	Token        string `json:"token"`
 	RefreshToken string `json:"refresh_token"`
+	// CsrfToken is only set in the cookie mode, the tokens are in cookies then
+	CsrfToken string `json:"csrf_token,omitempty"`
 }
 
 type MemberDataShown struct {
@@ -99,10 +137,30 @@ type MemberDataShown struct {
 	TokenBroadcast string    `json:"token_broadcast" bson:"token_broadcast"`
 	LastLogin      time.Time `json:"last_login" bson:"last_login"`
 	DeviceId       string    `json:"id_device" bson:"id_device"`
+	Roles          []string  `json:"roles" bson:"roles"`
 	// Info
 	PhoneNumber string `json:"phone_number" bson:"phone_number"`
 	Email       string `json:"email" bson:"email"`
 	MemberPhoto string `json:"photo_member" bson:"photo_member"`
+	// the stored objects of the uploaded photo are not shown
+	MemberPhotoThumbnails map[string]string `json:"photo_member_thumbnails" bson:"photo_member_thumbnails"`
+	MemberPhotoKeys       []string          `json:"-" bson:"photo_member_keys"`
+
+	// Verification
+	EmailVerifiedAt *time.Time `json:"email_verified_at" bson:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at" bson:"phone_verified_at"`
+
+	// Suspension
+	SuspendReason  string     `json:"suspend_reason" bson:"suspend_reason"`
+	SuspendedBy    string     `json:"suspended_by" bson:"suspended_by"`
+	SuspendedAt    *time.Time `json:"suspended_at" bson:"suspended_at"`
+	SuspendedUntil *time.Time `json:"suspended_until" bson:"suspended_until"`
+
+	// Deletion, a deleted member is kept until it is purged
+	DeletedAt *time.Time `json:"deleted_at" bson:"deleted_at"`
+	DeletedBy string     `json:"deleted_by" bson:"deleted_by"`
+
+	CredentialVersion int `json:"-" bson:"credential_version"`
 }
 
 type MemberDataFind struct {
@@ -120,6 +178,9 @@ type MemberDataFind struct {
 	// Info
 	PhoneNumber string `json:"phone_number"`
 	Email       string `json:"email"`
+
+	// WithDeleted also match the soft deleted members, they are excluded by default
+	WithDeleted bool `form:"with_deleted"`
 }
 
 func (r CreateMemberData) ValidateCreate() error {
@@ -144,7 +205,6 @@ func (r CreateMemberData) ValidateCreate() error {
 }
 
 func (r MemberData) ToShown() MemberDataShown {
-	fmt.Println("MAP DATA TO SHOWN", r)
 	return MemberDataShown{
 		ID:         r.ID.String(),
 		Username:   r.Username,
@@ -157,11 +217,74 @@ func (r MemberData) ToShown() MemberDataShown {
 		LastLogin:      r.LastLogin,
 		TokenBroadcast: r.TokenBroadcast,
 		DeviceId:       r.DeviceId,
+		Roles:          r.Roles,
 
 		// Info
 		PhoneNumber: r.PhoneNumber,
 		Email:       r.Email,
 		MemberPhoto: r.MemberPhoto,
+
+		MemberPhotoThumbnails: r.MemberPhotoThumbnails,
+		MemberPhotoKeys:       r.MemberPhotoKeys,
+
+		// Verification
+		EmailVerifiedAt: r.EmailVerifiedAt,
+		PhoneVerifiedAt: r.PhoneVerifiedAt,
+
+		// Suspension
+		SuspendReason:  r.SuspendReason,
+		SuspendedBy:    r.SuspendedBy,
+		SuspendedAt:    r.SuspendedAt,
+		SuspendedUntil: r.SuspendedUntil,
+
+		// Deletion
+		DeletedAt: r.DeletedAt,
+		DeletedBy: r.DeletedBy,
+
+		CredentialVersion: r.CredentialVersion,
+	}
+}
+
+// GetRoles return the default role for member without any role
+func (r MemberDataShown) GetRoles() []string {
+	if len(r.Roles) == 0 {
+		return []string{RoleMember}
+	}
+	return r.Roles
+}
+
+// HasRole use the default role of a member without any role
+func (r MemberDataShown) HasRole(role string) bool {
+	for _, memberRole := range r.GetRoles() {
+		if memberRole == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (r MemberDataShown) ToResAuth(token string, refreshToken string) MemberResAuth {
+	return MemberResAuth{
+		ID:             r.ID,
+		Username:       r.Username,
+		Fullname:       r.Fullname,
+		MemberType:     r.MemberType,
+		IsSuspend:      r.IsSuspend,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+		LastLogin:      r.LastLogin,
+		TokenBroadcast: r.TokenBroadcast,
+		DeviceId:       r.DeviceId,
+		Roles:          r.GetRoles(),
+		PhoneNumber:    r.PhoneNumber,
+		Email:          r.Email,
+		MemberPhoto:    r.MemberPhoto,
+		Token:          token,
+		RefreshToken:   refreshToken,
+
+		// Verification
+		EmailVerifiedAt: r.EmailVerifiedAt,
+		PhoneVerifiedAt: r.PhoneVerifiedAt,
 	}
 }
 
@@ -181,6 +304,7 @@ func NewMemberData(req CreateMemberData) (*MemberData, error) {
 	obj.CreatedAt = time.Now()
 	obj.UpdatedAt = time.Now()
 	obj.IsSuspend = false
+	obj.Roles = []string{RoleMember}
 
 	obj.MemberType = slug.Make(strings.ToLower(obj.MemberType))
 
@@ -197,5 +321,6 @@ const FullNameMustNotEmpty domerror.ErrorType = "ER1000 username must not empty"
 const PasswordMustNotEmpty domerror.ErrorType = "ER1000 password must not empty"      //
 const MemberTypeMustNotEmpty domerror.ErrorType = "ER1000 member type must not empty" //
 const PhoneNumberOrEmailMustNotEmpty domerror.ErrorType = "ER1000 Phone Number or Email must be filled"
+const MemberNotFound domerror.ErrorType = "ER1001 member is not found"
 
 //const UsernameMustNotEmpty domerror.ErrorType = "ER1000 username must not empty" //
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+// MemberDeletion is the deletion part of a member, it is compared for the audit log
+type MemberDeletion struct {
+	DeletedAt *time.Time `json:"deleted_at"`
+	DeletedBy string     `json:"deleted_by"`
+}
+
+// DeleteMemberReq soft delete a member, the member can be restored until it is purged
+type DeleteMemberReq struct {
+	MemberId  string
+	DeletedBy string
+	// ExpiredAt is the time every token issued before the deletion has expired
+	ExpiredAt time.Time
+
+	// StepUpMaxAge is only set when the member delete itself, it then need a recent authentication
+	AuthTime     time.Time
+	StepUpMaxAge time.Duration
+	// GrantedPermissions are the permissions of DeletedBy, another member holding more can not be deleted
+	GrantedPermissions []string
+}
+
+// RestoreMemberReq and PurgeMemberReq carry the permissions of the admin, a member holding more
+// can not be restored nor purged
+type RestoreMemberReq struct {
+	MemberId           string
+	GrantedPermissions []string
+}
+
+type PurgeMemberReq struct {
+	MemberId           string
+	GrantedPermissions []string
+}
+
+// DeleteMemberRes keep the member before the change for the audit log, it is the result of the restore too
+type DeleteMemberRes struct {
+	Member   MemberDataShown
+	Previous MemberDataShown
+}
+
+func (r MemberDataShown) IsDeleted() bool {
+	return r.DeletedAt != nil
+}
+
+func (r MemberDataShown) Deletion() MemberDeletion {
+	return MemberDeletion{
+		DeletedAt: r.DeletedAt,
+		DeletedBy: r.DeletedBy,
+	}
+}
+
+const MemberNotDeleted domerror.ErrorType = "ER1002 member is not deleted"
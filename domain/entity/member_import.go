@@ -0,0 +1,194 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/lib/core/spreadsheet"
+)
+
+// Member import statuses, in a dry run created means the row would be created
+const (
+	MemberImportCreated = "created"
+	MemberImportSkipped = "skipped"
+	MemberImportFailed  = "failed"
+)
+
+// memberImportRequiredColumns must be in the header, email, phone_number and photo_member are optional
+// and the other columns are ignored
+var memberImportRequiredColumns = []string{"username", "fullname", "password", "member_type"}
+
+// memberImportMaxColumns is the widest header accepted, the required and optional columns need far less
+const memberImportMaxColumns = 64
+
+// MemberImportConfig limit the imported file, the rows are validated and written BatchSize at a time
+type MemberImportConfig struct {
+	MaxSize   int64
+	MaxRows   int
+	BatchSize int
+}
+
+// WithDefault limit a file to 10240 KB and 10000 rows written 500 at a time when the value is not set
+func (r MemberImportConfig) WithDefault() MemberImportConfig {
+	if r.MaxSize <= 0 {
+		r.MaxSize = 10240 * 1024
+	}
+	if r.MaxRows <= 0 {
+		r.MaxRows = 10000
+	}
+	if r.BatchSize <= 0 {
+		r.BatchSize = 500
+	}
+	return r
+}
+
+// MemberImportRow is a member read from the file, Line is its line or row number in the file
+type MemberImportRow struct {
+	Line   int
+	Member CreateMemberData
+}
+
+type ImportMemberReq struct {
+	Rows      []MemberImportRow
+	DryRun    bool
+	BatchSize int
+}
+
+// ImportMemberRes report every row in the order of the file
+type ImportMemberRes struct {
+	DryRun  bool                    `json:"dry_run"`
+	Total   int                     `json:"total"`
+	Created int                     `json:"created"`
+	Skipped int                     `json:"skipped"`
+	Failed  int                     `json:"failed"`
+	Rows    []MemberImportRowResult `json:"rows"`
+}
+
+// MemberImportRowResult is skipped when the username, email or phone number is already taken,
+// failed when the row is invalid or could not be written
+type MemberImportRowResult struct {
+	Line     int    `json:"line"`
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	MemberId string `json:"id_member,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ReadMemberImportRows read the csv or xlsx file, the reading stop as soon as the file has more rows
+// or columns than an import accept
+func ReadMemberImportRows(format string, data []byte, config MemberImportConfig) ([]MemberImportRow, error) {
+	limit := spreadsheet.Limit{MaxColumns: memberImportMaxColumns}
+	if config.MaxRows > 0 {
+		// the header is a row too
+		limit.MaxRows = config.MaxRows + 1
+	}
+
+	records, err := spreadsheet.Read(format, data, limit)
+	if errors.Is(err, spreadsheet.ErrTooManyRows) {
+		return nil, MemberImportTooManyRows
+	}
+	if errors.Is(err, spreadsheet.ErrTooManyColumns) {
+		return nil, MemberImportTooManyColumns
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMemberImportRows(records, config.MaxRows)
+}
+
+// NewMemberImportRows read the header from the first non blank row, the column names are not case sensitive.
+// The blank rows are ignored
+func NewMemberImportRows(records [][]string, maxRows int) ([]MemberImportRow, error) {
+	headerIndex := -1
+	for i, record := range records {
+		if len(record) > 0 {
+			headerIndex = i
+			break
+		}
+	}
+	if headerIndex < 0 {
+		return nil, MemberImportMustNotEmpty
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[headerIndex] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range memberImportRequiredColumns {
+		if _, exist := columns[name]; !exist {
+			return nil, MemberImportColumnMissing
+		}
+	}
+
+	rows := []MemberImportRow{}
+	for i, record := range records[headerIndex+1:] {
+		if len(record) == 0 {
+			continue
+		}
+		if maxRows > 0 && len(rows) >= maxRows {
+			return nil, MemberImportTooManyRows
+		}
+
+		cell := func(name string) string {
+			index, exist := columns[name]
+			if !exist || index >= len(record) {
+				return ""
+			}
+			return record[index]
+		}
+
+		// ValidateCreate read the email and the phone number, they are never nil
+		email, phoneNumber, memberPhoto := cell("email"), cell("phone_number"), cell("photo_member")
+		rows = append(rows, MemberImportRow{
+			Line: headerIndex + i + 2,
+			Member: CreateMemberData{
+				Username:    cell("username"),
+				Fullname:    cell("fullname"),
+				Password:    cell("password"),
+				MemberType:  cell("member_type"),
+				Email:       &email,
+				PhoneNumber: &phoneNumber,
+				MemberPhoto: &memberPhoto,
+			},
+		})
+	}
+	if len(rows) == 0 {
+		return nil, MemberImportMustNotEmpty
+	}
+
+	return rows, nil
+}
+
+// UniqueKeys are the username, email and phone number of the member which must not be used by another one
+func (r MemberData) UniqueKeys() []string {
+	keys := []string{"username:" + r.Username}
+	if r.Email != "" {
+		keys = append(keys, "email:"+r.Email)
+	}
+	if r.PhoneNumber != "" {
+		keys = append(keys, "phone_number:"+r.PhoneNumber)
+	}
+	return keys
+}
+
+// Add count the row result by status
+func (r *ImportMemberRes) Add(result MemberImportRowResult) {
+	switch result.Status {
+	case MemberImportCreated:
+		r.Created++
+	case MemberImportSkipped:
+		r.Skipped++
+	case MemberImportFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+const MemberImportMustNotEmpty domerror.ErrorType = "ER1000 import file must have a header and at least one member"
+const MemberImportColumnMissing domerror.ErrorType = "ER1002 import file must have the username, fullname, password and member_type columns"
+const MemberImportTooManyRows domerror.ErrorType = "ER1002 import file has too many rows"
+const MemberImportTooManyColumns domerror.ErrorType = "ER1002 import file has too many columns"
+const MemberImportFileTooLarge domerror.ErrorType = "ER1002 import file is too large"
+const MemberImportDuplicated domerror.ErrorType = "ER1002 username, email or phone number is already used by a previous row"
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"fmt"
+
+	"backend_base_app/domain/domerror"
+)
+
+// MemberPhotoConfig limit the uploaded photo, ThumbnailSizes are the sides in pixel of the square thumbnails
+type MemberPhotoConfig struct {
+	MaxSize        int64
+	ThumbnailSizes []int
+}
+
+// MemberPhotoContentTypes are the accepted photo formats, the value is the extension of the stored object
+var MemberPhotoContentTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// UploadMemberPhotoReq hold the uploaded file, ContentType is sniffed from the data,
+// the content type sent by the client is not trusted
+type UploadMemberPhotoReq struct {
+	MemberId    string
+	ContentType string
+	Data        []byte
+	Config      MemberPhotoConfig
+}
+
+// UploadMemberPhotoRes keep the member before the change for the audit log
+type UploadMemberPhotoRes struct {
+	Member   MemberDataShown
+	Previous MemberDataShown
+}
+
+// MemberPhotoData is the photo part of a member, it is compared for the audit log
+type MemberPhotoData struct {
+	MemberPhoto           string            `json:"photo_member"`
+	MemberPhotoThumbnails map[string]string `json:"photo_member_thumbnails"`
+}
+
+// StorageObject is a file saved in the object storage
+type StorageObject struct {
+	Key         string
+	ContentType string
+	Data        []byte
+}
+
+// ThumbnailData is a square thumbnail, Size is its side in pixel
+type ThumbnailData struct {
+	Size        int
+	ContentType string
+	Data        []byte
+}
+
+func (r UploadMemberPhotoReq) Validate() error {
+	if len(r.Data) == 0 {
+		return MemberPhotoMustNotEmpty
+	}
+	if r.Config.MaxSize > 0 && int64(len(r.Data)) > r.Config.MaxSize {
+		return MemberPhotoTooLarge
+	}
+	if _, allowed := MemberPhotoContentTypes[r.ContentType]; !allowed {
+		return MemberPhotoTypeNotSupported
+	}
+	return nil
+}
+
+// ObjectKey is the key of the photo or of one of its thumbnails when size is not 0,
+// the photo id change on every upload so a cached url is never stale
+func (r UploadMemberPhotoReq) ObjectKey(photoId string, size int, contentType string) string {
+	extension := MemberPhotoContentTypes[contentType]
+	if size == 0 {
+		return fmt.Sprintf("member/%s/photo-%s.%s", r.MemberId, photoId, extension)
+	}
+	return fmt.Sprintf("member/%s/photo-%s_%d.%s", r.MemberId, photoId, size, extension)
+}
+
+func (r MemberDataShown) Photo() MemberPhotoData {
+	return MemberPhotoData{
+		MemberPhoto:           r.MemberPhoto,
+		MemberPhotoThumbnails: r.MemberPhotoThumbnails,
+	}
+}
+
+const MemberPhotoMustNotEmpty domerror.ErrorType = "ER1000 photo must not empty"
+const MemberPhotoTooLarge domerror.ErrorType = "ER1002 photo is too large"
+const MemberPhotoTypeNotSupported domerror.ErrorType = "ER1002 photo must be a jpeg, png or gif image"
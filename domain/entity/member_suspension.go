@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+// MemberSuspension is the suspension part of a member, it is compared for the audit log
+type MemberSuspension struct {
+	IsSuspend      bool       `json:"is_suspend"`
+	SuspendReason  string     `json:"suspend_reason"`
+	SuspendedBy    string     `json:"suspended_by"`
+	SuspendedAt    *time.Time `json:"suspended_at"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
+
+type SuspendMemberReq struct {
+	MemberId string `json:"-"`
+	Reason   string `json:"reason"`
+	// SuspendedUntil is optional, the suspension is only lifted by an admin without it
+	SuspendedUntil *time.Time `json:"suspended_until"`
+
+	// filled from the token, not from the body
+	SuspendedBy string `json:"-"`
+	// ExpiredAt is the time every token issued before the suspension has expired
+	ExpiredAt time.Time `json:"-"`
+	// GrantedPermissions are the permissions of SuspendedBy, a member holding more can not be suspended
+	GrantedPermissions []string `json:"-"`
+}
+
+type UnsuspendMemberReq struct {
+	MemberId string `json:"-"`
+	Reason   string `json:"reason"`
+}
+
+// SuspendMemberRes keep the member before the change for the audit log
+type SuspendMemberRes struct {
+	Member   MemberDataShown
+	Previous MemberDataShown
+}
+
+func (r SuspendMemberReq) Validate() error {
+	if len(strings.TrimSpace(r.Reason)) == 0 {
+		return SuspendReasonMustNotEmpty
+	}
+	if r.SuspendedUntil != nil && !r.SuspendedUntil.After(time.Now()) {
+		return SuspendedUntilInvalid
+	}
+	if r.MemberId == r.SuspendedBy {
+		return MemberCanNotSuspendItself
+	}
+	return nil
+}
+
+func (r UnsuspendMemberReq) Validate() error {
+	if len(strings.TrimSpace(r.Reason)) == 0 {
+		return SuspendReasonMustNotEmpty
+	}
+	return nil
+}
+
+func (r MemberDataShown) Suspension() MemberSuspension {
+	return MemberSuspension{
+		IsSuspend:      r.IsSuspend,
+		SuspendReason:  r.SuspendReason,
+		SuspendedBy:    r.SuspendedBy,
+		SuspendedAt:    r.SuspendedAt,
+		SuspendedUntil: r.SuspendedUntil,
+	}
+}
+
+const SuspendReasonMustNotEmpty domerror.ErrorType = "ER1000 reason must not empty"
+const SuspendedUntilInvalid domerror.ErrorType = "ER1002 suspended_until must be in the future"
+const MemberCanNotSuspendItself domerror.ErrorType = "ER1002 member can not suspend itself"
+const MemberNotSuspended domerror.ErrorType = "ER1002 member is not suspended"
@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+// MemberProfile is the part of a member changed by UpdateMemberReq, it is compared for the audit log
+type MemberProfile struct {
+	Username        string     `json:"username"`
+	Fullname        string     `json:"fullname"`
+	PhoneNumber     string     `json:"phone_number"`
+	Email           string     `json:"email"`
+	MemberPhoto     string     `json:"photo_member"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
+}
+
+// UpdateMemberReq is a partial update, a field missing from the body is nil and is not changed
+type UpdateMemberReq struct {
+	MemberId    string  `json:"-"`
+	Username    *string `json:"username"`
+	Fullname    *string `json:"fullname"`
+	PhoneNumber *string `json:"phone_number"`
+	Email       *string `json:"email"`
+	MemberPhoto *string `json:"photo_member"`
+
+	// filled from the token and config, not from the body.
+	// StepUpMaxAge is only set when the member update itself, a changed email or phone number
+	// then need a recent authentication
+	AuthTime     time.Time     `json:"-"`
+	StepUpMaxAge time.Duration `json:"-"`
+	// UpdatedBy update another member only when it has every permission of that member
+	UpdatedBy          string   `json:"-"`
+	GrantedPermissions []string `json:"-"`
+}
+
+// UpdateMemberRes keep the member before the change for the audit log
+type UpdateMemberRes struct {
+	Member   MemberDataShown
+	Previous MemberDataShown
+}
+
+func (r UpdateMemberReq) Validate() error {
+	if r.Username == nil && r.Fullname == nil && r.PhoneNumber == nil && r.Email == nil && r.MemberPhoto == nil {
+		return MemberUpdateMustNotEmpty
+	}
+	if r.Username != nil && len(strings.TrimSpace(*r.Username)) == 0 {
+		return UsernameMustNotEmpty
+	}
+	if r.Fullname != nil && len(strings.TrimSpace(*r.Fullname)) == 0 {
+		return FullNameMustNotEmpty
+	}
+	return nil
+}
+
+// Changes return the bson fields which differ from the member, a changed email or phone number
+// is not verified anymore and a changed photo lose its thumbnails. The member must keep an email or a phone number
+func (r UpdateMemberReq) Changes(member MemberDataShown) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+
+	setField := func(field string, value *string, current string) bool {
+		if value == nil || strings.TrimSpace(*value) == current {
+			return false
+		}
+		fields[field] = strings.TrimSpace(*value)
+		return true
+	}
+
+	setField("username", r.Username, member.Username)
+	setField("fullname", r.Fullname, member.Fullname)
+	if setField("photo_member", r.MemberPhoto, member.MemberPhoto) {
+		// the thumbnails belong to the uploaded photo, its objects are removed on the next upload
+		fields["photo_member_thumbnails"] = nil
+	}
+	if setField("email", r.Email, member.Email) {
+		fields["email_verified_at"] = nil
+	}
+	if setField("phone_number", r.PhoneNumber, member.PhoneNumber) {
+		fields["phone_verified_at"] = nil
+	}
+
+	email, _ := fields["email"].(string)
+	if _, changed := fields["email"]; !changed {
+		email = member.Email
+	}
+	phoneNumber, _ := fields["phone_number"].(string)
+	if _, changed := fields["phone_number"]; !changed {
+		phoneNumber = member.PhoneNumber
+	}
+	if email == "" && phoneNumber == "" {
+		return nil, PhoneNumberOrEmailMustNotEmpty
+	}
+
+	return fields, nil
+}
+
+// UniqueMemberFields is the username, email and phone number of the changes to check against
+// the other members, only the changed ones are set
+func UniqueMemberFields(changes map[string]interface{}) MemberDataFind {
+	username, _ := changes["username"].(string)
+	email, _ := changes["email"].(string)
+	phoneNumber, _ := changes["phone_number"].(string)
+
+	return MemberDataFind{
+		Username:    username,
+		Email:       email,
+		PhoneNumber: phoneNumber,
+	}
+}
+
+func (r MemberDataShown) Profile() MemberProfile {
+	return MemberProfile{
+		Username:        r.Username,
+		Fullname:        r.Fullname,
+		PhoneNumber:     r.PhoneNumber,
+		Email:           r.Email,
+		MemberPhoto:     r.MemberPhoto,
+		EmailVerifiedAt: r.EmailVerifiedAt,
+		PhoneVerifiedAt: r.PhoneVerifiedAt,
+	}
+}
+
+const MemberUpdateMustNotEmpty domerror.ErrorType = "ER1000 at least one field must be updated"
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"strings"
+
+	"backend_base_app/domain/domerror"
+)
+
+const (
+	NotificationChannelEmail string = "email"
+	NotificationChannelSms   string = "sms"
+)
+
+type NotificationData struct {
+	Channel string
+	To      string
+	Subject string
+	Body    string
+}
+
+// NotificationAddress return the channel and the address the member can be reached on,
+// the requested channel is used when the member has it, otherwise email is preferred over sms
+func (r MemberDataShown) NotificationAddress(channel string) (string, string, error) {
+	email := strings.TrimSpace(r.Email)
+	phoneNumber := strings.TrimSpace(r.PhoneNumber)
+
+	switch channel {
+	case NotificationChannelEmail:
+		if email != "" {
+			return channel, email, nil
+		}
+	case NotificationChannelSms:
+		if phoneNumber != "" {
+			return channel, phoneNumber, nil
+		}
+	case "":
+		if email != "" {
+			return NotificationChannelEmail, email, nil
+		}
+		if phoneNumber != "" {
+			return NotificationChannelSms, phoneNumber, nil
+		}
+	default:
+		return "", "", NotificationChannelNotSupported.Var(channel)
+	}
+
+	return "", "", NotificationAddressNotFound
+}
+
+const NotificationChannelNotSupported domerror.ErrorType = "ER1002 %s is not recognized notification channel"
+const NotificationAddressNotFound domerror.ErrorType = "ER1012 member has no address for the notification channel"
@@ -0,0 +1,188 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionOidcState      string = "oidc_state"
+	CollectionMemberIdentity string = "member_identity"
+)
+
+// OidcStateData keep the login started by a member until the provider redirect back, ID is the hashed state.
+// The document is removed by the callback or once ExpiredAt has passed
+type OidcStateData struct {
+	ID           string    `json:"id" bson:"id"`
+	Provider     string    `json:"provider" bson:"provider"`
+	Nonce        string    `json:"-" bson:"nonce"`
+	CodeVerifier string    `json:"-" bson:"code_verifier"`
+	DeviceId     string    `json:"id_device" bson:"id_device"`
+	Platform     string    `json:"platform" bson:"platform"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+	ExpiredAt    time.Time `json:"expired_at" bson:"expired_at"`
+}
+
+// MemberIdentityData link an account of an identity provider to a member, ID is provider:subject
+type MemberIdentityData struct {
+	ID          string    `json:"id" bson:"id"`
+	Provider    string    `json:"provider" bson:"provider"`
+	Subject     string    `json:"subject" bson:"subject"`
+	MemberId    string    `json:"id_member" bson:"id_member"`
+	Email       string    `json:"email" bson:"email"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at" bson:"last_login_at"`
+}
+
+// OidcIdentity is read from the id token validated by the provider keys
+type OidcIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type OidcAuthorizationUrlReq struct {
+	Provider     string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+type OidcExchangeReq struct {
+	Provider     string
+	Code         string
+	CodeVerifier string
+	Nonce        string
+}
+
+// OidcLoginConfig is read from oidc config
+type OidcLoginConfig struct {
+	// AllowCreate create a member on the first login of an email which is not registered
+	AllowCreate bool
+	// MemberType is given to the created member
+	MemberType    string
+	StateLifetime time.Duration
+}
+
+type OidcStartReq struct {
+	Provider string `json:"-" form:"-"`
+	DeviceId string `json:"id_device" form:"id_device"`
+	Platform string `json:"platform" form:"platform"`
+
+	// filled from config, not from the query
+	Config OidcLoginConfig `json:"-" form:"-"`
+}
+
+type OidcStartRes struct {
+	AuthorizationUrl string    `json:"authorization_url"`
+	ExpiredAt        time.Time `json:"expired_at"`
+}
+
+type OidcCallbackReq struct {
+	Provider         string `json:"-" form:"-"`
+	Code             string `json:"code" form:"code"`
+	State            string `json:"state" form:"state"`
+	Error            string `json:"error" form:"error"`
+	ErrorDescription string `json:"error_description" form:"error_description"`
+
+	// filled from config, not from the query
+	Config OidcLoginConfig `json:"-" form:"-"`
+}
+
+// OidcCallbackRes carry the device of the login started by OidcStartReq
+type OidcCallbackRes struct {
+	MemberLoginRes
+	DeviceId string
+	Platform string
+}
+
+func (r OidcCallbackReq) Validate() error {
+	if r.Error != "" {
+		return OidcProviderError.Var(strings.TrimSpace(r.Error + " " + r.ErrorDescription))
+	}
+	if len(strings.TrimSpace(r.Code)) == 0 || len(strings.TrimSpace(r.State)) == 0 {
+		return OidcStateInvalid
+	}
+	return nil
+}
+
+// NewOidcStateData return the state data and the plain state sent to the provider.
+// The code verifier is 43 characters of base64url, as PKCE require
+func NewOidcStateData(req OidcStartReq) (*OidcStateData, string, error) {
+	state, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	nonce, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	codeVerifier, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &OidcStateData{
+		ID:           util.HashToken(state),
+		Provider:     req.Provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		DeviceId:     req.DeviceId,
+		Platform:     req.Platform,
+		CreatedAt:    time.Now(),
+		ExpiredAt:    time.Now().Add(req.Config.StateLifetime),
+	}, state, nil
+}
+
+func MemberIdentityId(provider, subject string) string {
+	return fmt.Sprintf("%s:%s", provider, subject)
+}
+
+func NewMemberIdentityData(identity OidcIdentity, memberId string) MemberIdentityData {
+	return MemberIdentityData{
+		ID:          MemberIdentityId(identity.Provider, identity.Subject),
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		MemberId:    memberId,
+		Email:       identity.Email,
+		CreatedAt:   time.Now(),
+		LastLoginAt: time.Now(),
+	}
+}
+
+// ToCreateMemberData is used by the just in time creation, the password is random and can be set
+// later with the forgot password flow
+func (r OidcIdentity) ToCreateMemberData(memberType string) (*CreateMemberData, error) {
+	password, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	fullname := strings.TrimSpace(r.Name)
+	if fullname == "" {
+		fullname = r.Email
+	}
+	email := r.Email
+	phoneNumber := ""
+
+	return &CreateMemberData{
+		Username:    r.Email,
+		Fullname:    fullname,
+		Password:    password,
+		MemberType:  memberType,
+		Email:       &email,
+		PhoneNumber: &phoneNumber,
+	}, nil
+}
+
+const OidcProviderError domerror.ErrorType = "ER1016 identity provider returned an error : %s"
+const OidcStateInvalid domerror.ErrorType = "ER1016 login state is invalid or expired"
+const OidcEmailNotVerified domerror.ErrorType = "ER1016 identity provider did not verify the email"
+const OidcMemberNotRegistered domerror.ErrorType = "ER1016 no member is registered with email %s"
+const OidcMemberNotLinkable domerror.ErrorType = "ER1016 member with email %s must login with password and verify the email first"
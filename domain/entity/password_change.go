@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+	// KeepCurrentSession logout the other sessions only and return new tokens for the current one,
+	// otherwise every session is logged out
+	KeepCurrentSession bool `json:"keep_current_session"`
+
+	// filled from the token, the request and config, not from the body
+	MemberId        string                `json:"-"`
+	SessionId       string                `json:"-"`
+	IpAddress       string                `json:"-"`
+	LoginProtection LoginProtectionConfig `json:"-"`
+	// RefreshTokenExpiredAt is the expiry of the new refresh token of the kept session
+	RefreshTokenExpiredAt time.Time `json:"-"`
+}
+
+// ChangePasswordRes hold the kept session and its new refresh token, they are nil when every session is logged out
+type ChangePasswordRes struct {
+	Member       MemberDataShown
+	Session      *SessionData
+	RefreshToken *RefreshTokenData
+}
+
+func (r ChangePasswordReq) Validate() error {
+	if len(strings.TrimSpace(r.CurrentPassword)) == 0 {
+		return CurrentPasswordMustNotEmpty
+	}
+	if len(strings.TrimSpace(r.NewPassword)) == 0 {
+		return PasswordMustNotEmpty
+	}
+	if r.CurrentPassword == r.NewPassword {
+		return NewPasswordSameAsCurrent
+	}
+	return nil
+}
+
+const CurrentPasswordMustNotEmpty domerror.ErrorType = "ER1000 current password must not empty"
+const NewPasswordSameAsCurrent domerror.ErrorType = "ER1002 new password must be different from the current password"
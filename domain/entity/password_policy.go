@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+const (
+	CollectionPasswordHistory string = "password_history"
+)
+
+// PasswordHistoryData keep the hashes of the last passwords of a member, newest first. ID is the member id
+type PasswordHistoryData struct {
+	ID        string    `json:"id" bson:"id"`
+	MemberId  string    `json:"id_member" bson:"id_member"`
+	Hashes    []string  `json:"-" bson:"hashes"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// CheckPasswordPolicyReq select the policy by MemberType, MemberId is empty for a new member
+type CheckPasswordPolicyReq struct {
+	MemberId   string
+	MemberType string
+	Password   string
+}
+
+type PasswordRuleViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError list every rule the password does not follow
+type PasswordPolicyError struct {
+	Violations []PasswordRuleViolation
+}
+
+func (e PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return PasswordPolicyNotMet.Var(strings.Join(messages, ", ")).Error()
+}
+
+func (e PasswordPolicyError) Code() string {
+	return PasswordPolicyNotMet.Code()
+}
+
+// Fields return the messages by request field, as the validation error of a request
+func (e PasswordPolicyError) Fields() map[string][]PasswordRuleViolation {
+	return map[string][]PasswordRuleViolation{
+		"password": e.Violations,
+	}
+}
+
+const PasswordPolicyNotMet domerror.ErrorType = "ER1017 password %s"
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionPasswordReset string = "password_reset"
+)
+
+// PasswordResetData is a single use reset token, ID is the sha256 of the token sent to the member.
+// The document is removed once ExpiredAt has passed
+type PasswordResetData struct {
+	ID        string     `json:"id" bson:"id"`
+	MemberId  string     `json:"id_member" bson:"id_member"`
+	Channel   string     `json:"channel" bson:"channel"`
+	IsUsed    bool       `json:"is_used" bson:"is_used"`
+	UsedAt    *time.Time `json:"used_at" bson:"used_at"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+	ExpiredAt time.Time  `json:"expired_at" bson:"expired_at"`
+}
+
+type ForgotPasswordReq struct {
+	Username string `json:"username"`
+	// Channel is email or sms, empty means any channel the member has
+	Channel string `json:"channel"`
+
+	// filled from config, not from the body
+	ResetUrl  string    `json:"-"`
+	ExpiredAt time.Time `json:"-"`
+}
+
+type ResetPasswordReq struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+
+	// RevokeExpiredAt is the time every token issued before the reset has expired
+	RevokeExpiredAt time.Time `json:"-"`
+}
+
+func (r ForgotPasswordReq) Validate() error {
+	if len(strings.TrimSpace(r.Username)) == 0 {
+		return UsernameMustNotEmpty
+	}
+	return nil
+}
+
+func (r ResetPasswordReq) Validate() error {
+	if len(strings.TrimSpace(r.Token)) == 0 {
+		return PasswordResetTokenInvalid
+	}
+	if len(strings.TrimSpace(r.Password)) == 0 {
+		return PasswordMustNotEmpty
+	}
+	return nil
+}
+
+// NewPasswordResetData return the data to store and the plain token to send
+func NewPasswordResetData(memberId, channel string, expiredAt time.Time) (*PasswordResetData, string, error) {
+	token, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &PasswordResetData{
+		ID:        util.HashToken(token),
+		MemberId:  memberId,
+		Channel:   channel,
+		CreatedAt: time.Now(),
+		ExpiredAt: expiredAt,
+	}, token, nil
+}
+
+// ToNotificationData build the message, {token} in the reset url is replaced by the token
+func (r PasswordResetData) ToNotificationData(to, resetUrl, token string) NotificationData {
+	link := strings.NewReplacer("{token}", token).Replace(resetUrl)
+
+	return NotificationData{
+		Channel: r.Channel,
+		To:      to,
+		Subject: "Reset your password",
+		Body:    "Use this link to reset your password, it expires at " + r.ExpiredAt.Format(time.RFC1123) + " : " + link,
+	}
+}
+
+const PasswordResetTokenInvalid domerror.ErrorType = "ER1012 password reset token is invalid or expired"
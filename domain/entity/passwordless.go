@@ -0,0 +1,215 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionPasswordlessChallenge string = "passwordless_challenge"
+)
+
+// PasswordlessChallengeData is the last magic link or code sent to a member, the link is sent by email
+// and the code by sms. Only the hash of the code is stored, the link is signed.
+// The document is removed once ExpiredAt has passed
+type PasswordlessChallengeData struct {
+	ID            string    `json:"id" bson:"id"`
+	MemberId      string    `json:"id_member" bson:"id_member"`
+	Channel       string    `json:"channel" bson:"channel"`
+	Target        string    `json:"target" bson:"target"`
+	CodeHash      string    `json:"-" bson:"code_hash"`
+	FailedAttempt int       `json:"failed_attempt" bson:"failed_attempt"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	ExpiredAt     time.Time `json:"expired_at" bson:"expired_at"`
+}
+
+// PasswordlessConfig is read from passwordless config
+type PasswordlessConfig struct {
+	// LinkUrl is the page opening the magic link, {token} is replaced by the token
+	LinkUrl      string
+	LinkSecret   string
+	LinkLifetime time.Duration
+	CodeLifetime time.Duration
+	// ResendInterval is the minimum time between two links or codes
+	ResendInterval time.Duration
+	// MaxAttempt is the number of wrong codes before the code is invalidated
+	MaxAttempt int
+	// MemberTypes can login without password, empty means every member type
+	MemberTypes []string
+}
+
+type StartPasswordlessReq struct {
+	Username string `json:"username"`
+	// Channel is email for a magic link or phone for a code
+	Channel string `json:"channel"`
+
+	// filled from config, not from the body
+	Config PasswordlessConfig `json:"-"`
+}
+
+// CompletePasswordlessReq is completed either with the token of the magic link,
+// or with the username and the code
+type CompletePasswordlessReq struct {
+	Token          string `json:"token"`
+	Username       string `json:"username"`
+	Code           string `json:"code"`
+	TokenBroadcast string `json:"token_broadcast"`
+	DeviceId       string `json:"id_device"`
+	Platform       string `json:"platform"`
+
+	// filled from the request and config, not from the body
+	UserAgent            string             `json:"-"`
+	IpAddress            string             `json:"-"`
+	RequiredVerification map[string]string  `json:"-"`
+	Config               PasswordlessConfig `json:"-"`
+}
+
+func (r StartPasswordlessReq) Validate() error {
+	if len(strings.TrimSpace(r.Username)) == 0 {
+		return UsernameMustNotEmpty
+	}
+	return validateVerificationChannel(r.Channel)
+}
+
+func (r CompletePasswordlessReq) Validate() error {
+	if len(strings.TrimSpace(r.Token)) != 0 {
+		return nil
+	}
+	if len(strings.TrimSpace(r.Username)) == 0 {
+		return UsernameMustNotEmpty
+	}
+	if len(strings.TrimSpace(r.Code)) != VerificationCodeLength {
+		return PasswordlessChallengeInvalid
+	}
+	return nil
+}
+
+// GetRequiredVerification fallback to the default requirement when the member type has none
+func (r CompletePasswordlessReq) GetRequiredVerification(memberType string) string {
+	return MemberReqAuth{RequiredVerification: r.RequiredVerification}.GetRequiredVerification(memberType)
+}
+
+// AuthMethod is the amr of the login, mail for the magic link and sms for the code
+func (r CompletePasswordlessReq) AuthMethod() string {
+	if len(strings.TrimSpace(r.Token)) != 0 {
+		return AuthMethodEmail
+	}
+	return AuthMethodSms
+}
+
+// AllowMemberType is true when the member type can login without password
+func (r PasswordlessConfig) AllowMemberType(memberType string) bool {
+	return len(r.MemberTypes) == 0 || slices.Contains(r.MemberTypes, memberType)
+}
+
+// NewPasswordlessChallengeData return the challenge to store and the link token or the code to send
+func NewPasswordlessChallengeData(member MemberDataShown, channel string, config PasswordlessConfig, now time.Time) (*PasswordlessChallengeData, string, error) {
+	target, _ := member.VerificationTarget(channel)
+	if target == "" {
+		return nil, "", VerificationTargetNotFound.Var(channel)
+	}
+
+	obj := &PasswordlessChallengeData{
+		ID:        util.GenerateUuidWithoutDash(),
+		MemberId:  member.ID,
+		Channel:   channel,
+		Target:    target,
+		CreatedAt: now,
+	}
+
+	if channel == VerificationChannelEmail {
+		obj.ExpiredAt = now.Add(config.LinkLifetime)
+		return obj, obj.signLink(config.LinkSecret), nil
+	}
+
+	code, err := util.GenerateRandomDigits(VerificationCodeLength)
+	if err != nil {
+		return nil, "", err
+	}
+	obj.ExpiredAt = now.Add(config.CodeLifetime)
+	obj.CodeHash = obj.hashCode(code)
+
+	return obj, code, nil
+}
+
+// PasswordlessChallengeIdOf return the challenge id of a magic link token
+func PasswordlessChallengeIdOf(token string) (string, bool) {
+	id, _, found := strings.Cut(token, ".")
+	return id, found && id != ""
+}
+
+// CheckResend return PasswordlessTooManyRequest while a new link or code can not be sent yet
+func (r PasswordlessChallengeData) CheckResend(config PasswordlessConfig, now time.Time) error {
+	if now.Before(r.CreatedAt.Add(config.ResendInterval)) {
+		return PasswordlessTooManyRequest.Var(secondsUntil(now, r.CreatedAt.Add(config.ResendInterval)))
+	}
+	return nil
+}
+
+func (r PasswordlessChallengeData) IsUsable(config PasswordlessConfig, now time.Time) bool {
+	if !now.Before(r.ExpiredAt) {
+		return false
+	}
+	return config.MaxAttempt <= 0 || r.FailedAttempt < config.MaxAttempt
+}
+
+// VerifyLink check the signature of a magic link token, the signature cover the member and the expiry
+func (r PasswordlessChallengeData) VerifyLink(token string, config PasswordlessConfig, now time.Time) bool {
+	if r.Channel != VerificationChannelEmail || !r.IsUsable(config, now) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(r.signLink(config.LinkSecret))) == 1
+}
+
+// VerifyCode does not count the attempt, the caller count it atomically before and pass the challenge
+// as it was before the attempt
+func (r PasswordlessChallengeData) VerifyCode(code string, config PasswordlessConfig, now time.Time) bool {
+	if r.Channel != VerificationChannelPhone || r.CodeHash == "" || !r.IsUsable(config, now) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.CodeHash), []byte(r.hashCode(code))) == 1
+}
+
+func (r PasswordlessChallengeData) ToNotificationData(secret string, config PasswordlessConfig) NotificationData {
+	if r.Channel == VerificationChannelEmail {
+		link := strings.NewReplacer("{token}", secret).Replace(config.LinkUrl)
+		return NotificationData{
+			Channel: NotificationChannelEmail,
+			To:      r.Target,
+			Subject: "Your login link",
+			Body:    "Use this link to login, it expires at " + r.ExpiredAt.Format(time.RFC1123) + " : " + link,
+		}
+	}
+
+	return NotificationData{
+		Channel: NotificationChannelSms,
+		To:      r.Target,
+		Subject: "Login code",
+		Body:    fmt.Sprintf("Your login code is %s, it expires at %s", secret, r.ExpiredAt.Format(time.RFC1123)),
+	}
+}
+
+// signLink return <id>.<signature>, the signature is the hmac of the id, the member and the expiry
+func (r PasswordlessChallengeData) signLink(secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%s:%s:%d", r.ID, r.MemberId, r.ExpiredAt.Unix())))
+	return r.ID + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hashCode salt the code with the challenge id, so the same code does not give the same hash
+func (r PasswordlessChallengeData) hashCode(code string) string {
+	return util.HashToken(r.ID + ":" + code)
+}
+
+const PasswordlessChallengeInvalid domerror.ErrorType = "ER1020 login link or code is invalid or expired"
+const PasswordlessTooManyRequest domerror.ErrorType = "ER1020 too many login link or code requests, retry after %d seconds"
+const PasswordlessNotAllowed domerror.ErrorType = "ER1020 member type %s can not login without password"
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+// ReauthenticateReq prove again the identity of a logged in member before a sensitive operation,
+// with its password, a second factor code or both
+type ReauthenticateReq struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+
+	// filled from the token, the request and config, not from the body
+	MemberId        string                `json:"-"`
+	SessionId       string                `json:"-"`
+	IpAddress       string                `json:"-"`
+	LoginProtection LoginProtectionConfig `json:"-"`
+	Config          TwoFactorConfig       `json:"-"`
+}
+
+// ReauthenticateRes return the session with its new auth_time and amr
+type ReauthenticateRes struct {
+	Member  MemberDataShown
+	Session SessionData
+}
+
+func (r ReauthenticateReq) Validate() error {
+	if len(r.Password) == 0 && len(strings.TrimSpace(r.Code)) == 0 {
+		return ReauthenticationMethodMustNotEmpty
+	}
+	return nil
+}
+
+// AuthMethods is the amr of the reauthentication
+func (r ReauthenticateReq) AuthMethods() []string {
+	hasCode := len(strings.TrimSpace(r.Code)) != 0
+	switch {
+	case len(r.Password) != 0 && hasCode:
+		return []string{AuthMethodPassword, AuthMethodOtp, AuthMethodMfa}
+	case hasCode:
+		return []string{AuthMethodOtp}
+	default:
+		return []string{AuthMethodPassword}
+	}
+}
+
+// CheckRecentAuthentication return ReauthenticationRequired when the last authentication is older than maxAge,
+// a zero authTime is never recent
+func CheckRecentAuthentication(authTime time.Time, maxAge time.Duration, now time.Time) error {
+	if authTime.IsZero() || now.Sub(authTime) > maxAge {
+		return ReauthenticationRequired
+	}
+	return nil
+}
+
+const ReauthenticationMethodMustNotEmpty domerror.ErrorType = "ER1000 password or code must not empty"
+const ReauthenticationRequired domerror.ErrorType = "ER1022 recent authentication is required, reauthenticate then retry"
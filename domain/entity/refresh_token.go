@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionRefreshToken string = "refresh_token"
+)
+
+// RefreshTokenData is the persisted state of a single refresh token.
+// Every login start a new family, every refresh replace the token with a new one in the same family
+type RefreshTokenData struct {
+	ID        string     `json:"id" bson:"id"`
+	FamilyId  string     `json:"id_family" bson:"id_family"`
+	ParentId  string     `json:"id_parent" bson:"id_parent"`
+	MemberId  string     `json:"id_member" bson:"id_member"`
+	DeviceId  string     `json:"id_device" bson:"id_device"`
+	IsUsed    bool       `json:"is_used" bson:"is_used"`
+	UsedAt    *time.Time `json:"used_at" bson:"used_at"`
+	IsRevoked bool       `json:"is_revoked" bson:"is_revoked"`
+	RevokedAt *time.Time `json:"revoked_at" bson:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+	ExpiredAt time.Time  `json:"expired_at" bson:"expired_at"`
+}
+
+type CreateRefreshTokenData struct {
+	MemberId string
+	DeviceId string
+	// FamilyId is empty when a new family is started
+	FamilyId  string
+	ParentId  string
+	ExpiredAt time.Time
+}
+
+type RefreshAuthReq struct {
+	Token     AuthRefreshToken
+	ExpiredAt time.Time
+}
+
+type RefreshAuthRes struct {
+	Member       MemberDataShown
+	RefreshToken RefreshTokenData
+	Session      SessionData
+}
+
+func NewRefreshTokenData(req CreateRefreshTokenData) RefreshTokenData {
+	id := util.GenerateUuidWithoutDash()
+
+	familyId := req.FamilyId
+	if familyId == "" {
+		familyId = util.GenerateUuidWithoutDash()
+	}
+
+	return RefreshTokenData{
+		ID:        id,
+		FamilyId:  familyId,
+		ParentId:  req.ParentId,
+		MemberId:  req.MemberId,
+		DeviceId:  req.DeviceId,
+		CreatedAt: time.Now(),
+		ExpiredAt: req.ExpiredAt,
+	}
+}
+
+func (r RefreshTokenData) ToAuthRefreshToken() AuthRefreshToken {
+	return AuthRefreshToken{
+		Id:       r.MemberId,
+		DeviceId: r.DeviceId,
+		TokenId:  r.ID,
+		FamilyId: r.FamilyId,
+	}
+}
+
+func (r RefreshTokenData) IsExpired() bool {
+	return time.Now().After(r.ExpiredAt)
+}
+
+const RefreshTokenInvalid domerror.ErrorType = "ER1008 refresh token is invalid"
+const RefreshTokenExpired domerror.ErrorType = "ER1008 refresh token is expired"
+const RefreshTokenRevoked domerror.ErrorType = "ER1008 refresh token has been revoked"
+const RefreshTokenReuseDetected domerror.ErrorType = "ER1008 refresh token has been used, all sessions of this token are revoked"
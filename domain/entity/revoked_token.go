@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"backend_base_app/domain/domerror"
+)
+
+const (
+	CollectionRevokedToken string = "revoked_token"
+)
+
+const (
+	RevokedTokenKindToken  string = "token"
+	RevokedTokenKindMember string = "member"
+)
+
+// RevokedTokenData is either a single revoked token id,
+// or a member watermark revoking every token issued before IssuedBefore.
+// The document is removed once ExpiredAt has passed, when the revoked tokens are expired anyway
+type RevokedTokenData struct {
+	ID           string     `json:"id" bson:"id"`
+	Kind         string     `json:"kind" bson:"kind"`
+	MemberId     string     `json:"id_member" bson:"id_member"`
+	TokenId      string     `json:"id_token" bson:"id_token"`
+	IssuedBefore *time.Time `json:"issued_before" bson:"issued_before"`
+	CreatedAt    time.Time  `json:"created_at" bson:"created_at"`
+	ExpiredAt    time.Time  `json:"expired_at" bson:"expired_at"`
+}
+
+type LogoutReq struct {
+	MemberId  string
+	TokenId   string
+	FamilyId  string
+	ExpiredAt time.Time
+}
+
+type LogoutAllReq struct {
+	MemberId  string
+	ExpiredAt time.Time
+}
+
+type CheckRevokedTokenReq struct {
+	MemberId string
+	TokenId  string
+	IssuedAt time.Time
+}
+
+func NewRevokedTokenData(memberId, tokenId string, expiredAt time.Time) RevokedTokenData {
+	return RevokedTokenData{
+		ID:        fmt.Sprintf("%s:%s", RevokedTokenKindToken, tokenId),
+		Kind:      RevokedTokenKindToken,
+		MemberId:  memberId,
+		TokenId:   tokenId,
+		CreatedAt: time.Now(),
+		ExpiredAt: expiredAt,
+	}
+}
+
+func NewRevokedMemberData(memberId string, expiredAt time.Time) RevokedTokenData {
+	// iat only has second precision, round up so a token issued in the same second is revoked too
+	issuedBefore := time.Now().Truncate(time.Second).Add(time.Second)
+	return RevokedTokenData{
+		ID:           fmt.Sprintf("%s:%s", RevokedTokenKindMember, memberId),
+		Kind:         RevokedTokenKindMember,
+		MemberId:     memberId,
+		IssuedBefore: &issuedBefore,
+		CreatedAt:    time.Now(),
+		ExpiredAt:    expiredAt,
+	}
+}
+
+const TokenHasBeenRevoked domerror.ErrorType = "ER1008 token has been revoked"
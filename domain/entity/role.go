@@ -0,0 +1,189 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+
+	"github.com/gosimple/slug"
+)
+
+const (
+	CollectionRole       string = "role"
+	CollectionPermission string = "permission"
+)
+
+const (
+	PermissionAll         string = "*"
+	PermissionMemberRead  string = "member:read"
+	PermissionMemberWrite string = "member:write"
+	PermissionMemberPurge string = "member:purge"
+	PermissionRoleRead    string = "role:read"
+	PermissionRoleWrite   string = "role:write"
+	PermissionApiKeyRead  string = "api_key:read"
+	PermissionApiKeyWrite string = "api_key:write"
+	PermissionAuditRead   string = "audit:read"
+)
+
+const (
+	RoleSuperadmin string = "superadmin"
+	// RoleMember is given to every member without any role
+	RoleMember string = "member"
+)
+
+type PermissionData struct {
+	ID          string `json:"id" bson:"id"`
+	Description string `json:"description" bson:"description"`
+}
+
+type RoleData struct {
+	ID          string    `json:"id" bson:"id"`
+	Name        string    `json:"name" bson:"name"`
+	Description string    `json:"description" bson:"description"`
+	Permissions []string  `json:"permissions" bson:"permissions"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+type CreateRoleData struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+
+	// GrantedPermissions are the permissions of the member creating the role, a role can not get more
+	GrantedPermissions []string `json:"-"`
+}
+
+type UpdateRoleData struct {
+	ID          string   `json:"-"`
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+
+	// GrantedPermissions are the permissions of the member updating the role, a role can not get more
+	GrantedPermissions []string `json:"-"`
+}
+
+type DeleteRoleReq struct {
+	ID string
+
+	// GrantedPermissions are the permissions of the member deleting the role, the role is taken from
+	// every member so it can not hold more
+	GrantedPermissions []string
+}
+
+type RoleDataFind struct {
+	Name string `form:"name"`
+}
+
+type AssignMemberRoleReq struct {
+	MemberId string   `json:"-"`
+	Roles    []string `json:"roles"`
+
+	// GrantedPermissions are the permissions of the member assigning the roles, a role holding
+	// more permissions can not be given nor taken
+	GrantedPermissions []string `json:"-"`
+}
+
+// AssignMemberRoleRes keep the roles before the change for the audit log
+type AssignMemberRoleRes struct {
+	Member        MemberDataShown
+	PreviousRoles []string
+}
+
+// UpdateRoleRes keep the role before the change for the audit log
+type UpdateRoleRes struct {
+	Role     RoleData
+	Previous RoleData
+}
+
+// DefaultPermissions are registered on startup, a role can only hold these permissions
+var DefaultPermissions = []PermissionData{
+	{ID: PermissionAll, Description: "every permission"},
+	{ID: PermissionMemberRead, Description: "read member data"},
+	{ID: PermissionMemberWrite, Description: "create and update member data"},
+	{ID: PermissionMemberPurge, Description: "permanently remove deleted members"},
+	{ID: PermissionRoleRead, Description: "read roles and permissions"},
+	{ID: PermissionRoleWrite, Description: "manage roles and assign them to member"},
+	{ID: PermissionApiKeyRead, Description: "read api keys"},
+	{ID: PermissionApiKeyWrite, Description: "issue and revoke api keys"},
+	{ID: PermissionAuditRead, Description: "read the security audit log"},
+}
+
+// DefaultRoles are created on startup when they do not exist yet
+var DefaultRoles = []CreateRoleData{
+	{Name: RoleSuperadmin, Description: "full access", Permissions: []string{PermissionAll}},
+	{Name: RoleMember, Description: "default member", Permissions: []string{PermissionMemberRead}},
+}
+
+func (r CreateRoleData) ValidateCreate() error {
+	if len(strings.TrimSpace(r.Name)) == 0 {
+		return RoleNameMustNotEmpty
+	}
+	return nil
+}
+
+func NewRoleData(req CreateRoleData) (*RoleData, error) {
+	err := req.ValidateCreate()
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := req.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return &RoleData{
+		ID:          slug.Make(strings.ToLower(req.Name)),
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}, nil
+}
+
+// HasPermission check a single permission, PermissionAll grant everything
+func (r RoleData) HasPermission(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == PermissionAll || p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateGrantedPermissions reject the permissions which are not granted to the member making the change,
+// only a member holding PermissionAll can grant PermissionAll
+func ValidateGrantedPermissions(grantedPermissions []string, permissions []string) error {
+	granted := RoleData{Permissions: grantedPermissions}
+	for _, permission := range permissions {
+		if !granted.HasPermission(permission) {
+			return PermissionNotGranted.Var(permission)
+		}
+	}
+	return nil
+}
+
+// ValidateGrantedRoles reject the change of a member whose roles hold a permission which is not granted
+// to the member making the change, so a member can not act on a more privileged one
+func ValidateGrantedRoles(grantedPermissions []string, roles []*RoleData) error {
+	for _, role := range roles {
+		err := ValidateGrantedPermissions(grantedPermissions, role.Permissions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const RoleNameMustNotEmpty domerror.ErrorType = "ER1000 role name must not empty"
+const RoleNotFound domerror.ErrorType = "ER1001 role %s is not found"
+const RoleAlreadyExist domerror.ErrorType = "ER1006 role %s already exist"
+const PermissionNotFound domerror.ErrorType = "ER1001 permission %s is not found"
+const RoleIsProtected domerror.ErrorType = "ER1009 role %s is protected"
+const PermissionNotGranted domerror.ErrorType = "ER1009 permission %s is not granted to your role"
+const LastSuperadminRole domerror.ErrorType = "ER1009 the last superadmin can not lose the superadmin role"
+const LastSuperadmin domerror.ErrorType = "ER1009 the last superadmin can not be %s"
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionSession string = "session"
+)
+
+// Authentication methods of the amr claim, the names follow RFC 8176 when it has one
+const (
+	AuthMethodPassword  = "pwd"
+	AuthMethodOtp       = "otp"
+	AuthMethodMfa       = "mfa"
+	AuthMethodSms       = "sms"
+	AuthMethodEmail     = "mail"
+	AuthMethodFederated = "fed"
+)
+
+// SessionData is one record per login. The session id is also the family id of its refresh tokens
+type SessionData struct {
+	ID         string     `json:"id" bson:"id"`
+	MemberId   string     `json:"id_member" bson:"id_member"`
+	DeviceId   string     `json:"id_device" bson:"id_device"`
+	Platform   string     `json:"platform" bson:"platform"`
+	UserAgent  string     `json:"user_agent" bson:"user_agent"`
+	IpAddress  string     `json:"ip_address" bson:"ip_address"`
+	IsRevoked  bool       `json:"is_revoked" bson:"is_revoked"`
+	RevokedAt  *time.Time `json:"revoked_at" bson:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" bson:"last_seen_at"`
+	ExpiredAt  time.Time  `json:"expired_at" bson:"expired_at"`
+	// AuthTime is the last time the member proved its identity in this session, at login or reauthentication
+	AuthTime time.Time `json:"auth_time" bson:"auth_time"`
+	Amr      []string  `json:"amr" bson:"amr"`
+}
+
+type SessionDataShown struct {
+	SessionData
+	IsCurrent bool `json:"is_current"`
+}
+
+type CreateSessionReq struct {
+	MemberId  string
+	DeviceId  string
+	Platform  string
+	UserAgent string
+	IpAddress string
+	// Amr are the authentication methods used by the login
+	Amr []string
+	// MaxActive is the number of concurrent sessions allowed, 0 means unlimited
+	MaxActive int
+	ExpiredAt time.Time
+}
+
+type CreateSessionRes struct {
+	Session      SessionData
+	RefreshToken RefreshTokenData
+}
+
+type CheckSessionReq struct {
+	MemberId  string
+	SessionId string
+}
+
+type RevokeSessionReq struct {
+	MemberId  string
+	SessionId string
+}
+
+func NewSessionData(req CreateSessionReq) SessionData {
+	now := time.Now()
+	return SessionData{
+		ID:         util.GenerateUuidWithoutDash(),
+		MemberId:   req.MemberId,
+		DeviceId:   req.DeviceId,
+		Platform:   req.Platform,
+		UserAgent:  req.UserAgent,
+		IpAddress:  req.IpAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiredAt:  req.ExpiredAt,
+		AuthTime:   now,
+		Amr:        req.Amr,
+	}
+}
+
+func (r SessionData) IsActive() bool {
+	return !r.IsRevoked && time.Now().Before(r.ExpiredAt)
+}
+
+const SessionNotFound domerror.ErrorType = "ER1010 session not found"
+const SessionHasBeenRevoked domerror.ErrorType = "ER1010 session has been revoked or logged in on other device"
@@ -0,0 +1,249 @@
+package entity
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/lib/core/totp"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionTwoFactor string = "two_factor"
+)
+
+// RecoveryCodeLength is the number of digits of a recovery code, it is longer than a totp code
+// so both can be sent in the same field
+const RecoveryCodeLength = 10
+
+// TwoFactorData hold the totp secret of a member, ID is id_member.
+// The document is created by the enrollment and IsEnabled is set once the first code is confirmed
+type TwoFactorData struct {
+	ID        string `json:"id" bson:"id"`
+	MemberId  string `json:"id_member" bson:"id_member"`
+	Secret    string `json:"-" bson:"secret"`
+	IsEnabled bool   `json:"is_enabled" bson:"is_enabled"`
+	// RecoveryCodes are hashed, a used code is removed
+	RecoveryCodes []string `json:"-" bson:"recovery_codes"`
+	// LastUsedCounter is the time step of the last accepted code, a code is never accepted twice
+	LastUsedCounter int64      `json:"-" bson:"last_used_counter"`
+	EnabledAt       *time.Time `json:"enabled_at" bson:"enabled_at"`
+	CreatedAt       time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// TwoFactorConfig is read from two_factor config
+type TwoFactorConfig struct {
+	Issuer string
+	// Skew is the number of time steps accepted before and after the current one
+	Skew              int
+	RecoveryCodeCount int
+	ChallengeLifetime time.Duration
+}
+
+type EnrollTwoFactorReq struct {
+	MemberId    string
+	AccountName string
+	Config      TwoFactorConfig
+}
+
+type EnrollTwoFactorRes struct {
+	Secret          string `json:"secret"`
+	ProvisioningUri string `json:"provisioning_uri"`
+}
+
+type ConfirmTwoFactorReq struct {
+	Code string `json:"code"`
+
+	// filled from the token and config, not from the body
+	MemberId string          `json:"-"`
+	Config   TwoFactorConfig `json:"-"`
+}
+
+type ConfirmTwoFactorRes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type DisableTwoFactorReq struct {
+	Code string `json:"code"`
+
+	// filled from the token and config, not from the body
+	MemberId string          `json:"-"`
+	Config   TwoFactorConfig `json:"-"`
+}
+
+// TwoFactorLoginReq exchange the challenge token returned by the login for the member tokens
+type TwoFactorLoginReq struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	TokenBroadcast string `json:"token_broadcast"`
+	Platform       string `json:"platform"`
+
+	// filled from the challenge token, the request and config, not from the body
+	MemberId           string                `json:"-"`
+	ChallengeId        string                `json:"-"`
+	ChallengeIssuedAt  time.Time             `json:"-"`
+	ChallengeExpiredAt time.Time             `json:"-"`
+	DeviceId           string                `json:"-"`
+	UserAgent          string                `json:"-"`
+	IpAddress          string                `json:"-"`
+	LoginProtection    LoginProtectionConfig `json:"-"`
+	Config             TwoFactorConfig       `json:"-"`
+}
+
+// MemberLoginRes is the result of the password check, Member is not logged in yet when TwoFactorRequired
+type MemberLoginRes struct {
+	Member            MemberDataShown
+	TwoFactorRequired bool
+}
+
+// TwoFactorCodeUse is the accepted code, either the totp time step or the recovery code hash is set
+type TwoFactorCodeUse struct {
+	MemberId         string
+	Counter          int64
+	RecoveryCodeHash string
+}
+
+type TwoFactorChallengeRes struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiredAt         time.Time `json:"expired_at"`
+}
+
+func (r ConfirmTwoFactorReq) Validate() error {
+	return validateTwoFactorCode(r.Code)
+}
+
+func (r DisableTwoFactorReq) Validate() error {
+	return validateTwoFactorCode(r.Code)
+}
+
+func (r TwoFactorLoginReq) Validate() error {
+	if len(strings.TrimSpace(r.ChallengeToken)) == 0 {
+		return TwoFactorChallengeMustNotEmpty
+	}
+	return validateTwoFactorCode(r.Code)
+}
+
+func validateTwoFactorCode(code string) error {
+	if len(strings.TrimSpace(code)) == 0 {
+		return TwoFactorCodeMustNotEmpty
+	}
+	return nil
+}
+
+func NewTwoFactorData(memberId string) (*TwoFactorData, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorData{
+		ID:        memberId,
+		MemberId:  memberId,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+func (r TwoFactorData) ToEnrollRes(issuer, accountName string) EnrollTwoFactorRes {
+	return EnrollTwoFactorRes{
+		Secret:          r.Secret,
+		ProvisioningUri: totp.ProvisioningURI(issuer, accountName, r.Secret),
+	}
+}
+
+// VerifyTotp return the time step of a valid totp code which has not been used yet
+func (r TwoFactorData) VerifyTotp(code string, config TwoFactorConfig, now time.Time) (int64, bool) {
+	counter, ok := totp.Validate(r.Secret, code, now, config.Skew)
+	if !ok || counter <= r.LastUsedCounter {
+		return 0, false
+	}
+	return counter, true
+}
+
+// VerifyRecoveryCode return the hash of a recovery code which has not been used yet
+func (r TwoFactorData) VerifyRecoveryCode(code string) (string, bool) {
+	code = normalizeRecoveryCode(code)
+	if len(code) != RecoveryCodeLength {
+		return "", false
+	}
+
+	hash := r.hashRecoveryCode(code)
+	found := false
+	for _, recoveryCode := range r.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(recoveryCode), []byte(hash)) == 1 {
+			found = true
+		}
+	}
+
+	return hash, found
+}
+
+// VerifyCode accept a totp code or, once enabled, a recovery code
+func (r TwoFactorData) VerifyCode(code string, config TwoFactorConfig, now time.Time) (TwoFactorCodeUse, bool) {
+	if counter, ok := r.VerifyTotp(code, config, now); ok {
+		return TwoFactorCodeUse{MemberId: r.MemberId, Counter: counter}, true
+	}
+	if !r.IsEnabled {
+		return TwoFactorCodeUse{}, false
+	}
+	if hash, ok := r.VerifyRecoveryCode(code); ok {
+		return TwoFactorCodeUse{MemberId: r.MemberId, RecoveryCodeHash: hash}, true
+	}
+	return TwoFactorCodeUse{}, false
+}
+
+// Enable store the first confirmed time step and new recovery codes, the plain codes are returned
+// to be shown once
+func (r *TwoFactorData) Enable(counter int64, config TwoFactorConfig, now time.Time) ([]string, error) {
+	codes, err := r.RenewRecoveryCodes(config)
+	if err != nil {
+		return nil, err
+	}
+
+	r.IsEnabled = true
+	r.LastUsedCounter = counter
+	r.EnabledAt = &now
+	r.UpdatedAt = now
+
+	return codes, nil
+}
+
+func (r *TwoFactorData) RenewRecoveryCodes(config TwoFactorConfig) ([]string, error) {
+	codes := make([]string, 0, config.RecoveryCodeCount)
+	hashes := make([]string, 0, config.RecoveryCodeCount)
+
+	for i := 0; i < config.RecoveryCodeCount; i++ {
+		code, err := util.GenerateRandomDigits(RecoveryCodeLength)
+		if err != nil {
+			return nil, err
+		}
+		half := RecoveryCodeLength / 2
+		codes = append(codes, fmt.Sprintf("%s-%s", code[:half], code[half:]))
+		hashes = append(hashes, r.hashRecoveryCode(code))
+	}
+
+	r.RecoveryCodes = hashes
+
+	return codes, nil
+}
+
+// hashRecoveryCode salt the code with the member id, so the same code does not give the same hash
+func (r TwoFactorData) hashRecoveryCode(code string) string {
+	return util.HashToken(r.MemberId + ":" + code)
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code))
+}
+
+const TwoFactorCodeMustNotEmpty domerror.ErrorType = "ER1000 two factor code must not empty"
+const TwoFactorChallengeMustNotEmpty domerror.ErrorType = "ER1000 challenge token must not empty"
+const TwoFactorNotEnrolled domerror.ErrorType = "ER1014 two factor authentication is not enabled"
+const TwoFactorAlreadyEnabled domerror.ErrorType = "ER1014 two factor authentication is already enabled"
+const TwoFactorCodeInvalid domerror.ErrorType = "ER1014 two factor code is invalid"
@@ -0,0 +1,239 @@
+package entity
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend_base_app/domain/domerror"
+	"backend_base_app/shared/util"
+)
+
+const (
+	CollectionVerificationCode string = "verification_code"
+)
+
+const (
+	VerificationChannelEmail string = "email"
+	VerificationChannelPhone string = "phone"
+	// VerificationChannelAny is only used by the login requirement, one verified channel is enough
+	VerificationChannelAny string = "any"
+)
+
+const VerificationCodeLength = 6
+
+// VerificationCodeData hold the last code sent to a member on one channel, ID is id_member:channel.
+// The request counter is kept after the code is used, the document is removed once ExpiredAt has passed
+type VerificationCodeData struct {
+	ID              string    `json:"id" bson:"id"`
+	MemberId        string    `json:"id_member" bson:"id_member"`
+	Channel         string    `json:"channel" bson:"channel"`
+	Target          string    `json:"target" bson:"target"`
+	CodeHash        string    `json:"-" bson:"code_hash"`
+	FailedAttempt   int       `json:"failed_attempt" bson:"failed_attempt"`
+	RequestCount    int       `json:"request_count" bson:"request_count"`
+	WindowStartedAt time.Time `json:"window_started_at" bson:"window_started_at"`
+	LastSentAt      time.Time `json:"last_sent_at" bson:"last_sent_at"`
+	CodeExpiredAt   time.Time `json:"code_expired_at" bson:"code_expired_at"`
+	CreatedAt       time.Time `json:"created_at" bson:"created_at"`
+	ExpiredAt       time.Time `json:"expired_at" bson:"expired_at"`
+}
+
+// VerificationConfig is read from verification config
+type VerificationConfig struct {
+	CodeLifetime time.Duration
+	// ResendInterval is the minimum time between two codes
+	ResendInterval time.Duration
+	// MaxRequest is the number of codes which can be sent during RequestWindow
+	MaxRequest    int
+	RequestWindow time.Duration
+	// MaxAttempt is the number of wrong codes before the code is invalidated
+	MaxAttempt int
+}
+
+type RequestVerificationReq struct {
+	Username string `json:"username"`
+	Channel  string `json:"channel"`
+
+	// filled from config, not from the body
+	Config VerificationConfig `json:"-"`
+}
+
+type ConfirmVerificationReq struct {
+	Username string `json:"username"`
+	Channel  string `json:"channel"`
+	Code     string `json:"code"`
+
+	// filled from config, not from the body
+	Config VerificationConfig `json:"-"`
+}
+
+func (r RequestVerificationReq) Validate() error {
+	if len(strings.TrimSpace(r.Username)) == 0 {
+		return UsernameMustNotEmpty
+	}
+	return validateVerificationChannel(r.Channel)
+}
+
+func (r ConfirmVerificationReq) Validate() error {
+	if len(strings.TrimSpace(r.Username)) == 0 {
+		return UsernameMustNotEmpty
+	}
+	if len(strings.TrimSpace(r.Code)) != VerificationCodeLength {
+		return VerificationCodeInvalid
+	}
+	return validateVerificationChannel(r.Channel)
+}
+
+func validateVerificationChannel(channel string) error {
+	switch channel {
+	case VerificationChannelEmail, VerificationChannelPhone:
+		return nil
+	}
+	return VerificationChannelNotSupported.Var(channel)
+}
+
+func VerificationCodeId(memberId, channel string) string {
+	return fmt.Sprintf("%s:%s", memberId, channel)
+}
+
+// VerificationTarget return the address to verify on the channel and whether it is verified already
+func (r MemberDataShown) VerificationTarget(channel string) (string, bool) {
+	switch channel {
+	case VerificationChannelEmail:
+		return strings.TrimSpace(r.Email), r.EmailVerifiedAt != nil
+	case VerificationChannelPhone:
+		return strings.TrimSpace(r.PhoneNumber), r.PhoneVerifiedAt != nil
+	}
+	return "", false
+}
+
+// IsVerified check the login requirement, an empty requirement is always fulfilled
+func (r MemberDataShown) IsVerified(requirement string) bool {
+	switch requirement {
+	case VerificationChannelEmail:
+		return r.EmailVerifiedAt != nil
+	case VerificationChannelPhone:
+		return r.PhoneVerifiedAt != nil
+	case VerificationChannelAny:
+		return r.EmailVerifiedAt != nil || r.PhoneVerifiedAt != nil
+	}
+	return true
+}
+
+// GetRequiredVerification fallback to the default requirement when the member type has none
+func (r MemberReqAuth) GetRequiredVerification(memberType string) string {
+	if requirement, exist := r.RequiredVerification[memberType]; exist {
+		return requirement
+	}
+	return r.RequiredVerification["default"]
+}
+
+// CheckVerified return MemberNotVerified when the login requirement is not fulfilled
+func (r MemberDataShown) CheckVerified(requirement string) error {
+	if r.IsVerified(requirement) {
+		return nil
+	}
+	if requirement == VerificationChannelAny {
+		return MemberNotVerified.Var("email or phone")
+	}
+	return MemberNotVerified.Var(requirement)
+}
+
+func NewVerificationCodeData(memberId, channel string) VerificationCodeData {
+	return VerificationCodeData{
+		ID:        VerificationCodeId(memberId, channel),
+		MemberId:  memberId,
+		Channel:   channel,
+		CreatedAt: time.Now(),
+	}
+}
+
+// CheckRequest return VerificationCodeTooManyRequest while a new code can not be sent yet
+func (r VerificationCodeData) CheckRequest(config VerificationConfig, now time.Time) error {
+	if !r.LastSentAt.IsZero() && now.Before(r.LastSentAt.Add(config.ResendInterval)) {
+		return VerificationCodeTooManyRequest.Var(secondsUntil(now, r.LastSentAt.Add(config.ResendInterval)))
+	}
+
+	windowEndAt := r.WindowStartedAt.Add(config.RequestWindow)
+	if config.MaxRequest > 0 && now.Before(windowEndAt) && r.RequestCount >= config.MaxRequest {
+		return VerificationCodeTooManyRequest.Var(secondsUntil(now, windowEndAt))
+	}
+
+	return nil
+}
+
+// Renew replace the code, the plain code is returned to be sent
+func (r *VerificationCodeData) Renew(target string, config VerificationConfig, now time.Time) (string, error) {
+	code, err := util.GenerateRandomDigits(VerificationCodeLength)
+	if err != nil {
+		return "", err
+	}
+
+	if !now.Before(r.WindowStartedAt.Add(config.RequestWindow)) {
+		r.WindowStartedAt = now
+		r.RequestCount = 0
+	}
+
+	r.Target = target
+	r.CodeHash = r.hashCode(code)
+	r.FailedAttempt = 0
+	r.RequestCount++
+	r.LastSentAt = now
+	r.CodeExpiredAt = now.Add(config.CodeLifetime)
+	r.ExpiredAt = r.CodeExpiredAt
+	if windowEndAt := r.WindowStartedAt.Add(config.RequestWindow); windowEndAt.After(r.ExpiredAt) {
+		r.ExpiredAt = windowEndAt
+	}
+
+	return code, nil
+}
+
+// MatchCode does not check the attempt counter, see IsCodeUsable
+func (r VerificationCodeData) MatchCode(code string) bool {
+	return subtle.ConstantTimeCompare([]byte(r.CodeHash), []byte(r.hashCode(code))) == 1
+}
+
+func (r VerificationCodeData) IsCodeUsable(config VerificationConfig, now time.Time) bool {
+	if r.CodeHash == "" || !now.Before(r.CodeExpiredAt) {
+		return false
+	}
+	return config.MaxAttempt <= 0 || r.FailedAttempt < config.MaxAttempt
+}
+
+// Consume invalidate the code and keep the request counter
+func (r *VerificationCodeData) Consume() {
+	r.CodeHash = ""
+	r.CodeExpiredAt = time.Time{}
+}
+
+// hashCode salt the code with the document id, so the same code does not give the same hash
+func (r VerificationCodeData) hashCode(code string) string {
+	return util.HashToken(r.ID + ":" + code)
+}
+
+func (r VerificationCodeData) ToNotificationData(code string) NotificationData {
+	channel := NotificationChannelEmail
+	if r.Channel == VerificationChannelPhone {
+		channel = NotificationChannelSms
+	}
+
+	return NotificationData{
+		Channel: channel,
+		To:      r.Target,
+		Subject: "Verification code",
+		Body:    fmt.Sprintf("Your verification code is %s, it expires at %s", code, r.CodeExpiredAt.Format(time.RFC1123)),
+	}
+}
+
+func secondsUntil(now, until time.Time) int {
+	return int(until.Sub(now).Seconds()) + 1
+}
+
+const VerificationChannelNotSupported domerror.ErrorType = "ER1002 %s is not recognized verification channel"
+const VerificationCodeInvalid domerror.ErrorType = "ER1013 verification code is invalid or expired"
+const VerificationCodeTooManyRequest domerror.ErrorType = "ER1013 too many verification code requests, retry after %d seconds"
+const VerificationTargetNotFound domerror.ErrorType = "ER1013 member has no %s to verify"
+const MemberAlreadyVerified domerror.ErrorType = "ER1013 member %s is already verified"
+const MemberNotVerified domerror.ErrorType = "ER1013 member must verify the %s before login"
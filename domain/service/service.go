@@ -1,6 +1,7 @@
 package service
 
 import (
+	"backend_base_app/domain/entity"
 	"context"
 )
 
@@ -9,5 +10,29 @@ type GenerateIDService interface {
 }
 
 type EncryptPasswordService interface {
-	EncryptPassword(ctx context.Context, text string) string
+	EncryptPassword(ctx context.Context, text string) (string, error)
+	VerifyPassword(ctx context.Context, text string, hashed string) (bool, error)
+	NeedRehashPassword(ctx context.Context, hashed string) bool
+}
+
+type PasswordPolicyService interface {
+	CheckPasswordPolicy(ctx context.Context, req entity.CheckPasswordPolicyReq) error
+}
+
+type NotificationService interface {
+	SendNotification(ctx context.Context, obj entity.NotificationData) error
+}
+
+type IdentityProviderService interface {
+	OidcAuthorizationUrl(ctx context.Context, req entity.OidcAuthorizationUrlReq) (string, error)
+	OidcExchange(ctx context.Context, req entity.OidcExchangeReq) (*entity.OidcIdentity, error)
+}
+
+type ObjectStorageService interface {
+	PutObject(ctx context.Context, obj entity.StorageObject) (string, error)
+	DeleteObject(ctx context.Context, key string) error
+}
+
+type ImageService interface {
+	CreateThumbnails(ctx context.Context, data []byte, sizes []int) ([]entity.ThumbnailData, error)
 }
@@ -2,7 +2,12 @@ package apibaseappgateway
 
 import (
 	"backend_base_app/infrastructure/database"
+	"backend_base_app/lib/core/notifier"
+	"backend_base_app/lib/core/oidcclient"
+	"backend_base_app/lib/core/password"
+	"backend_base_app/lib/core/storage"
 	"fmt"
+	"sync"
 
 	cfg "backend_base_app/config/env"
 )
@@ -12,6 +17,15 @@ type GatewayApiBaseApp struct {
 	database string
 	*database.MongoWithTransactionImpl
 	*database.MongoWithoutTransactionImpl
+	passwordHasher *password.Manager
+	// passwordPolicies is keyed by member type
+	passwordPolicies     map[string]password.Policy
+	passwordHistoryLimit int
+	notifier             notifier.Notifier
+	oidcClient           *oidcclient.Client
+	storage              storage.Storage
+	// auditEventMu is shared by the copies of the gateway, the methods have value receivers
+	auditEventMu *sync.Mutex
 	//firebase
 	// AuthClientFirebase *auth.Client
 	// DbFirebase         *firebaseDb.Ref
@@ -29,15 +43,115 @@ func NewGateWayApiBaseApp(config cfg.Config) *GatewayApiBaseApp {
 
 	// TODO ADD DEFAULT USER
 
-	return &GatewayApiBaseApp{
+	passwordHasher, err := password.NewManager(newPasswordConfig(config))
+	if err != nil {
+		panic(err)
+	}
+
+	passwordPolicies, passwordHistoryLimit, err := newPasswordPolicies(config)
+	if err != nil {
+		panic(err)
+	}
+
+	messageNotifier, err := notifier.NewNotifier(notifier.Config{
+		Sink:     config.GetString("notifier.sink"),
+		FilePath: config.GetString("notifier.file_path"),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	var oidcConfig oidcclient.Config
+	if err := config.UnmarshalKey("oidc", &oidcConfig); err != nil {
+		panic(err)
+	}
+
+	var storageConfig storage.Config
+	if err := config.UnmarshalKey("storage", &storageConfig); err != nil {
+		panic(err)
+	}
+
+	objectStorage, err := storage.NewStorage(storageConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	gateway := &GatewayApiBaseApp{
 		// Cache:                       cacheConnection,
 		database:                    dbName,
 		MongoWithoutTransactionImpl: database.NewMongoWithoutTransactionImpl(db),
 		MongoWithTransactionImpl:    database.NewMongoWithTransactionImpl(db),
+		passwordHasher:              passwordHasher,
+		passwordPolicies:            passwordPolicies,
+		passwordHistoryLimit:        passwordHistoryLimit,
+		notifier:                    messageNotifier,
+		oidcClient:                  oidcclient.NewClient(oidcConfig),
+		storage:                     objectStorage,
+		auditEventMu:                &sync.Mutex{},
 		//firebase
 		// AuthClientFirebase: authClientFirebase,
 		// DbFirebase:       firebaseConnection,
 		// DBClientFirebase: dbClientFirebase,
 		// DatabaseFirebase: dbFirebase.DatabaseName,
 	}
+
+	gateway.prepareRefreshTokenCollection()
+	gateway.prepareRevokedTokenCollection()
+	gateway.prepareSessionCollection()
+	gateway.prepareLoginAttemptCollection()
+	gateway.preparePasswordResetCollection()
+	gateway.preparePasswordlessChallengeCollection()
+	gateway.prepareVerificationCodeCollection()
+	gateway.prepareTwoFactorCollection()
+	gateway.prepareApiKeyCollection()
+	gateway.prepareOidcCollection()
+	gateway.preparePasswordHistoryCollection()
+	gateway.prepareAuditEventCollection()
+	gateway.prepareRoleCollection(config.GetString("authorization.superadmin_username"))
+
+	return gateway
+}
+
+func newPasswordConfig(config cfg.Config) password.Config {
+	return password.Config{
+		Algorithm: config.GetString("password_hash.algorithm"),
+		Argon2id: password.Argon2idConfig{
+			MemoryKiB:   uint32(config.GetInt("password_hash.argon2id.memory_kib")),
+			Iterations:  uint32(config.GetInt("password_hash.argon2id.iterations")),
+			Parallelism: uint8(config.GetInt("password_hash.argon2id.parallelism")),
+			SaltLength:  uint32(config.GetInt("password_hash.argon2id.salt_length")),
+			KeyLength:   uint32(config.GetInt("password_hash.argon2id.key_length")),
+		},
+		Bcrypt: password.BcryptConfig{
+			Cost: config.GetInt("password_hash.bcrypt.cost"),
+		},
+	}
+}
+
+// newPasswordPolicies read password_policy.member_type and return the longest history size,
+// the default key is used for the other member types
+func newPasswordPolicies(config cfg.Config) (map[string]password.Policy, int, error) {
+	breached, err := password.LoadBreachedList(config.GetString("password_policy.breached_list_file"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	policyConfigs := map[string]password.PolicyConfig{}
+	if err := config.UnmarshalKey("password_policy.member_type", &policyConfigs); err != nil {
+		return nil, 0, err
+	}
+
+	policies := map[string]password.Policy{}
+	historyLimit := 0
+	for memberType, policyConfig := range policyConfigs {
+		policies[memberType] = password.NewPolicy(policyConfig, breached)
+		if policyConfig.HistorySize > historyLimit {
+			historyLimit = policyConfig.HistorySize
+		}
+	}
+	if _, exist := policies["default"]; !exist {
+		policies["default"] = password.NewPolicy(password.DefaultPolicyConfig, breached)
+	}
+
+	return policies, historyLimit, nil
 }
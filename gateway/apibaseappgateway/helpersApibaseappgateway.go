@@ -1,10 +1,12 @@
 package apibaseappgateway
 
 import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/lib/core/notifier"
+	"backend_base_app/lib/core/thumbnail"
 	"backend_base_app/shared/log"
 	"context"
-	"crypto/md5"
-	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -23,12 +25,137 @@ func (r GatewayApiBaseApp) GenerateID(ctx context.Context) string {
 	return id
 }
 
-func (r GatewayApiBaseApp) EncryptPassword(ctx context.Context, text string) string {
+func (r GatewayApiBaseApp) EncryptPassword(ctx context.Context, text string) (string, error) {
 	log.Info(ctx, "called")
 
-	pass := md5.Sum([]byte(text))
+	return r.passwordHasher.Hash(text)
+}
+
+func (r GatewayApiBaseApp) VerifyPassword(ctx context.Context, text string, hashed string) (bool, error) {
+	log.Info(ctx, "called")
+
+	return r.passwordHasher.Verify(text, hashed)
+}
+
+// VerifyDummyPassword spend the time of a password verification when there is no member to verify,
+// it never match
+func (r GatewayApiBaseApp) VerifyDummyPassword(ctx context.Context, text string) {
+	_, _ = r.VerifyPassword(ctx, text, r.passwordHasher.DummyHash())
+}
+
+func (r GatewayApiBaseApp) NeedRehashPassword(ctx context.Context, hashed string) bool {
+	return r.passwordHasher.NeedsRehash(hashed)
+}
+
+// CheckPasswordPolicy return PasswordPolicyError with every rule of the member type policy the password does not follow
+func (r GatewayApiBaseApp) CheckPasswordPolicy(ctx context.Context, req entity.CheckPasswordPolicyReq) error {
+	log.Info(ctx, "called")
+
+	policy, exist := r.passwordPolicies[req.MemberType]
+	if !exist {
+		policy = r.passwordPolicies["default"]
+	}
+
+	violations := []entity.PasswordRuleViolation{}
+	for _, violation := range policy.Check(req.Password) {
+		violations = append(violations, entity.PasswordRuleViolation{Rule: violation.Rule, Message: violation.Message})
+	}
+
+	if req.MemberId != "" && policy.HistorySize() > 0 {
+		history, err := r.FindOnePasswordHistoryByMemberId(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		for i, hashed := range history.Hashes {
+			if i >= policy.HistorySize() {
+				break
+			}
+			if used, _ := r.passwordHasher.Verify(req.Password, hashed); used {
+				violation := policy.HistoryViolation()
+				violations = append(violations, entity.PasswordRuleViolation{Rule: violation.Rule, Message: violation.Message})
+				break
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return entity.PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+func (r GatewayApiBaseApp) SendNotification(ctx context.Context, obj entity.NotificationData) error {
+	log.Info(ctx, "called")
+
+	return r.notifier.Send(ctx, notifier.Message{
+		Channel: obj.Channel,
+		To:      obj.To,
+		Subject: obj.Subject,
+		Body:    obj.Body,
+	})
+}
+
+func (r GatewayApiBaseApp) OidcAuthorizationUrl(ctx context.Context, req entity.OidcAuthorizationUrlReq) (string, error) {
+	log.Info(ctx, "called")
+
+	return r.oidcClient.AuthCodeURL(ctx, req.Provider, req.State, req.Nonce, req.CodeVerifier)
+}
+
+func (r GatewayApiBaseApp) OidcExchange(ctx context.Context, req entity.OidcExchangeReq) (*entity.OidcIdentity, error) {
+	log.Info(ctx, "called")
+
+	identity, err := r.oidcClient.Exchange(ctx, req.Provider, req.Code, req.CodeVerifier, req.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.OidcIdentity{
+		Provider:      identity.Provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Name:          identity.Name,
+	}, nil
+}
+
+func (r GatewayApiBaseApp) PutObject(ctx context.Context, obj entity.StorageObject) (string, error) {
+	log.Info(ctx, "called")
+
+	return r.storage.Put(ctx, obj.Key, obj.ContentType, obj.Data)
+}
+
+func (r GatewayApiBaseApp) DeleteObject(ctx context.Context, key string) error {
+	log.Info(ctx, "called")
+
+	return r.storage.Delete(ctx, key)
+}
+
+func (r GatewayApiBaseApp) CreateThumbnails(ctx context.Context, data []byte, sizes []int) ([]entity.ThumbnailData, error) {
+	log.Info(ctx, "called")
+
+	images, err := thumbnail.Generate(data, sizes)
+	if errors.Is(err, thumbnail.ErrUnsupportedFormat) {
+		return nil, entity.MemberPhotoTypeNotSupported
+	}
+	if errors.Is(err, thumbnail.ErrImageTooLarge) {
+		return nil, entity.MemberPhotoTooLarge
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails := make([]entity.ThumbnailData, 0, len(images))
+	for _, image := range images {
+		thumbnails = append(thumbnails, entity.ThumbnailData{
+			Size:        image.Size,
+			ContentType: image.ContentType,
+			Data:        image.Data,
+		})
+	}
 
-	return hex.EncodeToString(pass[:])
+	return thumbnails, nil
 }
 
 func testCache(cacheConnection *cache.Cache) {
@@ -41,7 +168,7 @@ func testCache(cacheConnection *cache.Cache) {
 		Value: obj,
 		TTL:   time.Hour,
 	}); err != nil {
-		fmt.Println("error >>>", err.Error)
+		fmt.Println("error >>>", err.Error())
 	} else {
 		fmt.Println("redis connected !")
 	}
@@ -0,0 +1,144 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// apiKeyLastUsedInterval avoid writing the key on every single request
+const apiKeyLastUsedInterval = time.Minute
+
+type ApiKeyRepo interface {
+	CreateApiKey(ctx context.Context, obj entity.ApiKeyData) error
+	FindOneApiKeyByPrefix(ctx context.Context, prefix string) (*entity.ApiKeyData, error)
+	FindAllApiKey(ctx context.Context, req entity.BaseReqFind) ([]*entity.ApiKeyData, int64, error)
+	RevokeApiKey(ctx context.Context, id string) error
+	TouchApiKey(ctx context.Context, id string) error
+}
+
+type apiKeyCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getApiKeyCollection() apiKeyCollection {
+	return apiKeyCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionApiKey),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareApiKeyCollection() {
+	coll := r.getApiKeyCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "prefix")
+}
+
+func (r GatewayApiBaseApp) CreateApiKey(ctx context.Context, obj entity.ApiKeyData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getApiKeyCollection()
+
+	info, err := coll.InsertOne(ctx, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// FindOneApiKeyByPrefix return ApiKeyInvalid when there is no key, the caller must still match the whole key
+func (r GatewayApiBaseApp) FindOneApiKeyByPrefix(ctx context.Context, prefix string) (*entity.ApiKeyData, error) {
+	log.Info(ctx, "called")
+
+	var resultApiKey entity.ApiKeyData
+
+	coll := r.getApiKeyCollection()
+	err := coll.FindOne(ctx, bson.M{"prefix": prefix}).Decode(&resultApiKey)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ApiKeyInvalid
+		}
+		return nil, err
+	}
+
+	return &resultApiKey, nil
+}
+
+func (r GatewayApiBaseApp) FindAllApiKey(ctx context.Context, req entity.BaseReqFind) ([]*entity.ApiKeyData, int64, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.ApiKeyData{}
+
+	coll := r.getApiKeyCollection()
+
+	criteria := bson.M{}
+	findData, _ := req.Value.(entity.ApiKeyDataFind)
+	if findData.Name != "" {
+		criteria["name"] = primitive.Regex{Pattern: findData.Name, Options: "i"}
+	}
+	if !findData.IncludeRevoked {
+		criteria["is_revoked"] = false
+	}
+
+	findOpts := gateway.BaseReqFindToOptOption(req)
+	if len(req.SortBy) == 0 {
+		findOpts.Sort = bson.D{{Key: "created_at", Value: -1}}
+	}
+
+	cursor, err := coll.Find(ctx, criteria, &findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, 0, err
+	}
+
+	count, err := coll.CountDocuments(ctx, criteria)
+
+	return objs, count, err
+}
+
+func (r GatewayApiBaseApp) RevokeApiKey(ctx context.Context, id string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getApiKeyCollection()
+
+	now := time.Now()
+	info, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": id},
+		bson.M{"$set": bson.M{"is_revoked": true, "revoked_at": now, "updated_at": now}},
+	)
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		return err
+	}
+
+	if info.MatchedCount == 0 {
+		return entity.ApiKeyNotFound.Var(id)
+	}
+
+	return nil
+}
+
+func (r GatewayApiBaseApp) TouchApiKey(ctx context.Context, id string) error {
+	coll := r.getApiKeyCollection()
+
+	now := time.Now()
+	_, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": id, "$or": bson.A{
+			bson.M{"last_used_at": nil},
+			bson.M{"last_used_at": bson.M{"$lt": now.Add(-apiKeyLastUsedInterval)}},
+		}},
+		bson.M{"$set": bson.M{"last_used_at": now}},
+	)
+
+	return err
+}
@@ -0,0 +1,163 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway"
+	"backend_base_app/shared/log"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// auditEventAppendRetry is the number of try when another instance has taken the sequence
+const auditEventAppendRetry = 5
+
+type AuditEventRepo interface {
+	AppendAuditEvent(ctx context.Context, obj entity.AuditEventData) (*entity.AuditEventData, error)
+	FindAllAuditEvent(ctx context.Context, req entity.BaseReqFind) ([]*entity.AuditEventData, int64, error)
+	FindAllAuditEventAfterSequence(ctx context.Context, sequence int64, limit int64) ([]*entity.AuditEventData, error)
+}
+
+type auditEventCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getAuditEventCollection() auditEventCollection {
+	return auditEventCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionAuditEvent),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareAuditEventCollection() {
+	coll := r.getAuditEventCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "sequence")
+}
+
+// AppendAuditEvent chain the event after the last one. The mutex serialize this instance,
+// the unique sequence reject an event chained at the same time by another instance
+func (r GatewayApiBaseApp) AppendAuditEvent(ctx context.Context, obj entity.AuditEventData) (*entity.AuditEventData, error) {
+	log.Info(ctx, "called")
+
+	r.auditEventMu.Lock()
+	defer r.auditEventMu.Unlock()
+
+	coll := r.getAuditEventCollection()
+
+	var err error
+	for try := 0; try < auditEventAppendRetry; try++ {
+		var last *entity.AuditEventData
+		last, err = coll.findLast(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		obj.Chain(last)
+
+		var info *mongo.InsertOneResult
+		info, err = coll.InsertOne(ctx, obj)
+		log.Info(ctx, "info >>> %v", info)
+		if err == nil {
+			return &obj, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+func (r auditEventCollection) findLast(ctx context.Context) (*entity.AuditEventData, error) {
+	var resultAuditEvent entity.AuditEventData
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+	err := r.FindOne(ctx, bson.M{}, opts).Decode(&resultAuditEvent)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &resultAuditEvent, nil
+}
+
+func (r GatewayApiBaseApp) FindAllAuditEvent(ctx context.Context, req entity.BaseReqFind) ([]*entity.AuditEventData, int64, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.AuditEventData{}
+
+	coll := r.getAuditEventCollection()
+
+	criteria := bson.M{}
+	findData, _ := req.Value.(entity.AuditEventDataFind)
+	if findData.Action != "" {
+		criteria["action"] = findData.Action
+	}
+	if findData.Outcome != "" {
+		criteria["outcome"] = findData.Outcome
+	}
+	if findData.ActorId != "" {
+		criteria["actor_id"] = findData.ActorId
+	}
+	if findData.TargetMemberId != "" {
+		criteria["target_member_id"] = findData.TargetMemberId
+	}
+	if findData.IpAddress != "" {
+		criteria["ip_address"] = findData.IpAddress
+	}
+	if findData.TraceId != "" {
+		criteria["trace_id"] = findData.TraceId
+	}
+	createdAt := bson.M{}
+	if findData.From != nil {
+		createdAt["$gte"] = *findData.From
+	}
+	if findData.To != nil {
+		createdAt["$lt"] = *findData.To
+	}
+	if len(createdAt) > 0 {
+		criteria["created_at"] = createdAt
+	}
+
+	findOpts := gateway.BaseReqFindToOptOption(req)
+	if len(req.SortBy) == 0 {
+		findOpts.Sort = bson.D{{Key: "sequence", Value: -1}}
+	}
+
+	cursor, err := coll.Find(ctx, criteria, &findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, 0, err
+	}
+
+	count, err := coll.CountDocuments(ctx, criteria)
+
+	return objs, count, err
+}
+
+// FindAllAuditEventAfterSequence return the next events in sequence order, used to walk the whole chain
+func (r GatewayApiBaseApp) FindAllAuditEventAfterSequence(ctx context.Context, sequence int64, limit int64) ([]*entity.AuditEventData, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.AuditEventData{}
+
+	coll := r.getAuditEventCollection()
+
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}}).SetLimit(limit)
+	cursor, err := coll.Find(ctx, bson.M{"sequence": bson.M{"$gt": sequence}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cursor.All(ctx, &objs)
+
+	return objs, err
+}
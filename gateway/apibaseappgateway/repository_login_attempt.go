@@ -0,0 +1,165 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoginAttemptRepo interface {
+	FindAllLoginAttemptByIds(ctx context.Context, ids []string) ([]*entity.LoginAttemptData, error)
+	FindAllLoginAttempt(ctx context.Context, req entity.BaseReqFind) ([]*entity.LoginAttemptData, int64, error)
+	IncrementLoginAttempt(ctx context.Context, obj entity.LoginAttemptData) (*entity.LoginAttemptData, error)
+	UpdateLoginAttempt(ctx context.Context, obj entity.LoginAttemptData) error
+	DeleteLoginAttempt(ctx context.Context, id string) error
+}
+
+type loginAttemptCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getLoginAttemptCollection() loginAttemptCollection {
+	return loginAttemptCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionLoginAttempt),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareLoginAttemptCollection() {
+	coll := r.getLoginAttemptCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedExpireAt(coll.Collection, "expired_at")
+}
+
+// activeLoginAttemptCriteria skip the document the TTL monitor has not removed yet
+func activeLoginAttemptCriteria(criteria bson.M) bson.M {
+	criteria["expired_at"] = bson.M{"$gt": time.Now()}
+	return criteria
+}
+
+func (r GatewayApiBaseApp) FindAllLoginAttemptByIds(ctx context.Context, ids []string) ([]*entity.LoginAttemptData, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.LoginAttemptData{}
+
+	coll := r.getLoginAttemptCollection()
+	cursor, err := coll.Find(ctx, activeLoginAttemptCriteria(bson.M{"id": bson.M{"$in": ids}}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+func (r GatewayApiBaseApp) FindAllLoginAttempt(ctx context.Context, req entity.BaseReqFind) ([]*entity.LoginAttemptData, int64, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.LoginAttemptData{}
+
+	coll := r.getLoginAttemptCollection()
+
+	criteria := bson.M{}
+	findData, _ := req.Value.(entity.LoginAttemptDataFind)
+	if findData.Kind != "" {
+		criteria["kind"] = findData.Kind
+	}
+	if findData.Key != "" {
+		criteria["key"] = findData.Key
+	}
+	if findData.OnlyLocked {
+		criteria["locked_until"] = bson.M{"$gt": time.Now()}
+	}
+	criteria = activeLoginAttemptCriteria(criteria)
+
+	findOpts := gateway.BaseReqFindToOptOption(req)
+	if len(req.SortBy) == 0 {
+		findOpts.Sort = bson.D{{Key: "last_failed_at", Value: -1}}
+	}
+
+	cursor, err := coll.Find(ctx, criteria, &findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, 0, err
+	}
+
+	count, err := coll.CountDocuments(ctx, criteria)
+
+	return objs, count, err
+}
+
+// IncrementLoginAttempt add a failed attempt atomically,
+// a document the TTL monitor has not removed yet start again from 1
+func (r GatewayApiBaseApp) IncrementLoginAttempt(ctx context.Context, obj entity.LoginAttemptData) (*entity.LoginAttemptData, error) {
+	log.Info(ctx, "called")
+
+	var resultLoginAttempt entity.LoginAttemptData
+
+	coll := r.getLoginAttemptCollection()
+
+	now := time.Now()
+	isExpired := bson.M{"$lte": bson.A{bson.M{"$ifNull": bson.A{"$expired_at", now}}, now}}
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"id":   obj.ID,
+			"kind": obj.Kind,
+			"key":  obj.Key,
+			"failed_count": bson.M{"$cond": bson.A{
+				isExpired,
+				1,
+				bson.M{"$add": bson.A{"$failed_count", 1}},
+			}},
+			"created_at":     bson.M{"$cond": bson.A{isExpired, now, "$created_at"}},
+			"last_failed_at": now,
+			"expired_at":     obj.ExpiredAt,
+		}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := coll.FindOneAndUpdate(ctx, bson.M{"id": obj.ID}, update, opts).Decode(&resultLoginAttempt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resultLoginAttempt, nil
+}
+
+func (r GatewayApiBaseApp) UpdateLoginAttempt(ctx context.Context, obj entity.LoginAttemptData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getLoginAttemptCollection()
+
+	info, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": obj.ID},
+		bson.M{"$set": bson.M{
+			"is_locked_out": obj.IsLockedOut,
+			"locked_until":  obj.LockedUntil,
+			"expired_at":    obj.ExpiredAt,
+		}},
+	)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+func (r GatewayApiBaseApp) DeleteLoginAttempt(ctx context.Context, id string) error {
+	log.Info(ctx, "called")
+
+	info, err := r.MongoWithTransactionImpl.DeleteByCustomId(ctx, r.database, entity.CollectionLoginAttempt, id)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"backend_base_app/gateway"
 	"backend_base_app/shared/dbhelpers"
 	"backend_base_app/shared/log"
+	"errors"
 	"fmt"
 	"time"
 
@@ -20,9 +21,40 @@ import (
 type CreateMemberDataRepo interface {
 	CreateMemberData(ctx context.Context, obj entity.MemberData) error
 	FindOneMemberDataById(ctx context.Context, id string) (*entity.MemberDataShown, error)
-	UpdateMemberData(ctx context.Context, memberData entity.MemberDataShown) (*entity.MemberDataShown, error)
+	UpdateMemberDataFields(ctx context.Context, id string, fields map[string]interface{}) (*entity.MemberDataShown, error)
+	CheckMemberDataTaken(ctx context.Context, id string, obj entity.MemberDataFind) error
 	FindAllMemberData(ctx context.Context, req entity.BaseReqFind) ([]*entity.MemberDataShown, int64, error)
 	MemberLoginAuthorization(ctx context.Context, obj entity.MemberReqAuth) (*entity.MemberDataShown, error)
+	VerifyMemberCredential(ctx context.Context, obj entity.MemberReqAuth) (*entity.MemberDataShown, error)
+	CompleteMemberLogin(ctx context.Context, member entity.MemberDataShown, deviceId string, tokenBroadcast string) (*entity.MemberDataShown, error)
+	UpdateMemberRoles(ctx context.Context, id string, roles []string) (*entity.MemberDataShown, error)
+	CountMemberByRole(ctx context.Context, role string) (int64, error)
+	FindOneMemberDataByUsername(ctx context.Context, username string) (*entity.MemberDataShown, error)
+	FindOneMemberDataByEmail(ctx context.Context, email string) (*entity.MemberDataShown, error)
+	UpdateMemberPassword(ctx context.Context, id string, plainPassword string) error
+	UpdateMemberVerified(ctx context.Context, id string, channel string, target string) error
+	SuspendMemberData(ctx context.Context, req entity.SuspendMemberReq) (*entity.MemberDataShown, error)
+	UnsuspendMemberData(ctx context.Context, id string, endedBefore *time.Time) (*entity.MemberDataShown, error)
+	FindAllMemberDataSuspensionEnded(ctx context.Context, now time.Time) ([]*entity.MemberDataShown, error)
+	FindOneMemberDataByIdWithDeleted(ctx context.Context, id string) (*entity.MemberDataShown, error)
+	SoftDeleteMemberData(ctx context.Context, id string, deletedBy string) (*entity.MemberDataShown, error)
+	RestoreMemberData(ctx context.Context, id string) (*entity.MemberDataShown, error)
+	PurgeMemberData(ctx context.Context, id string) error
+	FindTakenMemberKeys(ctx context.Context, objs []entity.MemberData) (map[string]bool, error)
+	CreateManyMemberData(ctx context.Context, objs []entity.MemberData) ([]error, error)
+}
+
+// memberCollectionsByMemberId hold documents of a member under id_member, they are removed with the member
+var memberCollectionsByMemberId = []string{
+	entity.CollectionSession,
+	entity.CollectionRefreshToken,
+	entity.CollectionRevokedToken,
+	entity.CollectionTwoFactor,
+	entity.CollectionMemberIdentity,
+	entity.CollectionPasswordHistory,
+	entity.CollectionPasswordReset,
+	entity.CollectionPasswordlessChallenge,
+	entity.CollectionVerificationCode,
 }
 
 type memberCollection struct {
@@ -35,6 +67,12 @@ func (r GatewayApiBaseApp) getMemberCollection() memberCollection {
 	}
 }
 
+// notDeletedMember exclude the soft deleted members from the filter, a missing deleted_at match nil too
+func notDeletedMember(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
 func getFilterKeyword(
 	obj entity.MemberDataFind,
 	onlySimiliar bool,
@@ -130,6 +168,9 @@ func getFilterKeyword(
 		criteriaKeyword = bson.M{"$or": keywordFilter}
 		allCriteria = append(allCriteria, criteriaKeyword)
 	}
+	if !obj.WithDeleted {
+		allCriteria = append(allCriteria, notDeletedMember(bson.M{}))
+	}
 
 	criteria := bson.M{}
 	if len(allCriteria) > 0 {
@@ -160,6 +201,8 @@ func (r GatewayApiBaseApp) CreateMemberData(ctx context.Context, obj entity.Memb
 			Username:    obj.Username,
 			PhoneNumber: obj.PhoneNumber,
 			Email:       obj.Email,
+			// a deleted member keep its username, email and phone number until it is purged
+			WithDeleted: true,
 		},
 		false,
 	)
@@ -174,23 +217,129 @@ func (r GatewayApiBaseApp) CreateMemberData(ctx context.Context, obj entity.Memb
 
 	err = dbhelpers.WithTransaction(ctx, r.MongoWithTransactionImpl, func(dbCtx context.Context) error {
 		info, err := memberCollection.InsertOne(ctx, obj)
-		log.Info(ctx, "info >>> ", info)
+		log.Info(ctx, "info >>> %v", info)
 		return err
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return r.AddPasswordHistory(ctx, obj.ID.String(), obj.Password)
+}
+
+// FindTakenMemberKeys return the UniqueKeys of the objs already used by a member, the deleted ones included
+func (r GatewayApiBaseApp) FindTakenMemberKeys(ctx context.Context, objs []entity.MemberData) (map[string]bool, error) {
+	log.Info(ctx, "called")
+
+	taken := map[string]bool{}
+	if len(objs) == 0 {
+		return taken, nil
+	}
+
+	usernames, emails, phoneNumbers := bson.A{}, bson.A{}, bson.A{}
+	for _, obj := range objs {
+		usernames = append(usernames, obj.Username)
+		if obj.Email != "" {
+			emails = append(emails, obj.Email)
+		}
+		if obj.PhoneNumber != "" {
+			phoneNumbers = append(phoneNumbers, obj.PhoneNumber)
+		}
+	}
+
+	coll := r.getMemberCollection()
+
+	cursor, err := coll.Find(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"username": bson.M{"$in": usernames}},
+			bson.M{"email": bson.M{"$in": emails}},
+			bson.M{"phone_number": bson.M{"$in": phoneNumbers}},
+		}},
+		options.Find().SetProjection(bson.M{"username": 1, "email": 1, "phone_number": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []entity.MemberData
+	if err := cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		for _, key := range member.UniqueKeys() {
+			taken[key] = true
+		}
+	}
+
+	return taken, nil
 }
 
+// CreateManyMemberData insert the objs in a single unordered bulk write, the returned errors are by index
+// of objs and nil for an inserted member. The password history of the inserted members is started
+func (r GatewayApiBaseApp) CreateManyMemberData(ctx context.Context, objs []entity.MemberData) ([]error, error) {
+	log.Info(ctx, "called")
+
+	rowErrs := make([]error, len(objs))
+	if len(objs) == 0 {
+		return rowErrs, nil
+	}
+
+	coll := r.getMemberCollection()
+
+	documents := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		documents = append(documents, obj)
+	}
+
+	info, err := coll.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+			return nil, err
+		}
+		for _, writeErr := range bulkErr.WriteErrors {
+			if writeErr.Index < 0 || writeErr.Index >= len(objs) {
+				continue
+			}
+			rowErrs[writeErr.Index] = errors.New(writeErr.Message)
+			if mongo.IsDuplicateKeyError(writeErr.WriteError) {
+				rowErrs[writeErr.Index] = DataRegistraionHasTaken
+			}
+		}
+	}
+
+	inserted := make([]entity.MemberData, 0, len(objs))
+	for i, obj := range objs {
+		if rowErrs[i] == nil {
+			inserted = append(inserted, obj)
+		}
+	}
+
+	return rowErrs, r.AddManyPasswordHistory(ctx, inserted)
+}
+
+// FindOneMemberDataById does not find a soft deleted member, see FindOneMemberDataByIdWithDeleted
 func (r GatewayApiBaseApp) FindOneMemberDataById(ctx context.Context, id string) (*entity.MemberDataShown, error) {
 	log.Info(ctx, "called")
 
+	return r.findOneMemberData(ctx, notDeletedMember(bson.M{"id": id}))
+}
+
+func (r GatewayApiBaseApp) FindOneMemberDataByIdWithDeleted(ctx context.Context, id string) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	return r.findOneMemberData(ctx, bson.M{"id": id})
+}
+
+func (r GatewayApiBaseApp) findOneMemberData(ctx context.Context, filter bson.M) (*entity.MemberDataShown, error) {
 	var (
 		resultMemberData entity.MemberDataShown
 		err              error
 	)
 
 	coll := r.getMemberCollection()
-	resCol := coll.FindOne(ctx, bson.M{"id": id})
+	resCol := coll.FindOne(ctx, filter)
 	err = resCol.Decode(&resultMemberData)
 	if err != nil {
 		log.Error(ctx, err.Error())
@@ -205,24 +354,58 @@ func (r GatewayApiBaseApp) FindOneMemberDataById(ctx context.Context, id string)
 	return &resultMemberData, nil
 }
 
-func (r GatewayApiBaseApp) UpdateMemberData(ctx context.Context, memberData entity.MemberDataShown) (*entity.MemberDataShown, error) {
+// UpdateMemberDataFields only set the given fields, the member is never written back whole
+// so a change made since it was read is kept. A deleted member is not updated
+func (r GatewayApiBaseApp) UpdateMemberDataFields(ctx context.Context, id string, fields map[string]interface{}) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	update := bson.M{"updated_at": time.Now().Local().UTC()}
+	for field, value := range fields {
+		update[field] = value
+	}
+
+	coll := r.getMemberCollection()
+
+	info, err := coll.UpdateOne(ctx, notDeletedMember(bson.M{"id": id}), bson.M{"$set": update})
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		return nil, err
+	}
+	if info.MatchedCount == 0 {
+		return nil, entity.MemberNotFound
+	}
+
+	return r.FindOneMemberDataById(ctx, id)
+}
 
+// CheckMemberDataTaken return DataRegistraionHasTaken when another member use the username, email or phone number.
+// Empty fields are not checked
+func (r GatewayApiBaseApp) CheckMemberDataTaken(ctx context.Context, id string, obj entity.MemberDataFind) error {
 	log.Info(ctx, "called")
 
-	clientData, err := r.FindOneMemberDataById(ctx, memberData.ID)
-	if err != nil || clientData == nil {
-		return clientData, err
+	if obj.Username == "" && obj.Email == "" && obj.PhoneNumber == "" {
+		return nil
 	}
 
-	memberData.UpdatedAt = time.Now().Local().UTC()
+	coll := r.getMemberCollection()
+
+	criteria := getFilterKeyword(entity.MemberDataFind{
+		Username:    obj.Username,
+		PhoneNumber: obj.PhoneNumber,
+		Email:       obj.Email,
+		WithDeleted: true,
+	}, false)
+	criteria["id"] = bson.M{"$ne": id}
 
-	info, err := r.MongoWithTransactionImpl.SaveOrUpdateByCustomId(ctx, r.database, entity.CollectionMember, memberData.ID, memberData)
-	log.Info(ctx, "info >>> ", info)
+	count, err := coll.CountDocuments(ctx, criteria)
 	if err != nil {
-		log.Info(ctx, "error >>> "+err.Error())
+		return err
+	}
+	if count > 0 {
+		return DataRegistraionHasTaken
 	}
 
-	return &memberData, err
+	return nil
 }
 
 func (r GatewayApiBaseApp) FindAllMemberData(ctx context.Context, req entity.BaseReqFind) ([]*entity.MemberDataShown, int64, error) {
@@ -258,36 +441,351 @@ func (r GatewayApiBaseApp) FindAllMemberData(ctx context.Context, req entity.Bas
 func (r GatewayApiBaseApp) MemberLoginAuthorization(ctx context.Context, obj entity.MemberReqAuth) (*entity.MemberDataShown, error) {
 	log.Info(ctx, "called")
 
+	resultMemberDataShown, err := r.VerifyMemberCredential(ctx, obj)
+	if err != nil {
+		return resultMemberDataShown, err
+	}
+
+	return r.CompleteMemberLogin(ctx, *resultMemberDataShown, obj.DeviceId, obj.TokenBroadcast)
+}
+
+// VerifyMemberCredential check the username and password without logging in,
+// the member is returned with the error when it is suspended
+func (r GatewayApiBaseApp) VerifyMemberCredential(ctx context.Context, obj entity.MemberReqAuth) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
 	var (
-		resultMemberDataShown *entity.MemberDataShown
-		err                   error
+		memberData entity.MemberData
+		err        error
 	)
 
 	coll := r.getMemberCollection()
 
-	encryptPassword := r.EncryptPassword(ctx, obj.Password)
-
-	err = coll.FindOne(ctx, bson.M{"username": obj.Username, "password": encryptPassword}).Decode(&resultMemberDataShown)
+	err = coll.FindOne(ctx, notDeletedMember(bson.M{"username": obj.Username})).Decode(&memberData)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// an unknown username answer as slowly as a wrong password
+			r.VerifyDummyPassword(ctx, obj.Password)
+			return nil, InvalidUsernameOrPassword
+		}
+		return nil, err
+	}
 
+	match, err := r.VerifyPassword(ctx, obj.Password, memberData.Password)
 	if err != nil {
-		return resultMemberDataShown, err
+		log.Error(ctx, err.Error())
+	}
+	if !match {
+		return nil, InvalidUsernameOrPassword
+	}
+
+	// upgrade legacy or outdated hash while the plain password is known
+	if r.NeedRehashPassword(ctx, memberData.Password) {
+		r.rehashMemberPassword(ctx, memberData.ID.String(), obj.Password)
 	}
 
+	resultMemberDataShown := memberData.ToShown()
+
 	if resultMemberDataShown.IsSuspend {
 		err = entity.NewMyError("Account is Suspended")
-		return resultMemberDataShown, err
+		return &resultMemberDataShown, err
 	}
 
+	return &resultMemberDataShown, nil
+}
+
+// CompleteMemberLogin store the last login and the device of a member whose credential are verified
+func (r GatewayApiBaseApp) CompleteMemberLogin(ctx context.Context, member entity.MemberDataShown, deviceId string, tokenBroadcast string) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
 	loc, _ := time.LoadLocation("Asia/Jakarta")
-	resultMemberDataShown.LastLogin = time.Now().In(loc)
 
-	if obj.DeviceId != "" {
-		resultMemberDataShown.DeviceId = obj.DeviceId
+	// only the login fields are set, the member was read before the password check
+	// and may have been suspended, deleted or given a new password since
+	fields := map[string]interface{}{"last_login": time.Now().In(loc)}
+	if deviceId != "" {
+		fields["id_device"] = deviceId
+	}
+	if tokenBroadcast != "" {
+		fields["token_broadcast"] = tokenBroadcast
+	}
+	return r.UpdateMemberDataFields(ctx, member.ID, fields)
+}
+
+func (r GatewayApiBaseApp) UpdateMemberRoles(ctx context.Context, id string, roles []string) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	_, err := r.FindOneMemberDataById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := r.MongoWithTransactionImpl.UpdateByCustomId(ctx, r.database, entity.CollectionMember, id, bson.M{
+		"roles":      roles,
+		"updated_at": time.Now().Local().UTC(),
+	})
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindOneMemberDataById(ctx, id)
+}
+
+// CountMemberByRole count the members holding the role which can use it, the deleted and suspended ones are not counted
+func (r GatewayApiBaseApp) CountMemberByRole(ctx context.Context, role string) (int64, error) {
+	log.Info(ctx, "called")
+
+	coll := r.getMemberCollection()
+	return coll.CountDocuments(ctx, notDeletedMember(bson.M{"roles": role, "is_suspend": bson.M{"$ne": true}}))
+}
+
+func (r GatewayApiBaseApp) FindOneMemberDataByUsername(ctx context.Context, username string) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	var resultMemberData entity.MemberDataShown
+
+	coll := r.getMemberCollection()
+	err := coll.FindOne(ctx, notDeletedMember(bson.M{"username": username})).Decode(&resultMemberData)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("member data not found")
+		}
+		return nil, err
+	}
+
+	return &resultMemberData, nil
+}
+
+// FindOneMemberDataByEmail return MemberNotFound when no member use the email
+func (r GatewayApiBaseApp) FindOneMemberDataByEmail(ctx context.Context, email string) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	var resultMemberData entity.MemberDataShown
+
+	coll := r.getMemberCollection()
+	err := coll.FindOne(ctx, notDeletedMember(bson.M{"email": email})).Decode(&resultMemberData)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.MemberNotFound
+		}
+		return nil, err
+	}
+
+	return &resultMemberData, nil
+}
+
+// UpdateMemberPassword hash the plain password with the configured algorithm before storing it,
+// it increase the credential version of the member
+func (r GatewayApiBaseApp) UpdateMemberPassword(ctx context.Context, id string, plainPassword string) error {
+	log.Info(ctx, "called")
+
+	encryptPassword, err := r.EncryptPassword(ctx, plainPassword)
+	if err != nil {
+		return err
+	}
+
+	// the new credential version reject every token issued with the previous password
+	coll := r.getMemberCollection()
+	info, err := coll.UpdateOne(ctx, bson.M{"id": id}, bson.M{
+		"$set": bson.M{"password": encryptPassword, "updated_at": time.Now().Local().UTC()},
+		"$inc": bson.M{"credential_version": 1},
+	})
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		return err
+	}
+
+	return r.AddPasswordHistory(ctx, id, encryptPassword)
+}
+
+// UpdateMemberVerified only verify the address the code was sent to,
+// it fails when the member has changed the address in the meantime
+func (r GatewayApiBaseApp) UpdateMemberVerified(ctx context.Context, id string, channel string, target string) error {
+	log.Info(ctx, "called")
+
+	addressField, verifiedAtField := "email", "email_verified_at"
+	if channel == entity.VerificationChannelPhone {
+		addressField, verifiedAtField = "phone_number", "phone_verified_at"
+	}
+
+	coll := r.getMemberCollection()
+
+	now := time.Now().Local().UTC()
+	info, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": id, addressField: target},
+		bson.M{"$set": bson.M{verifiedAtField: now, "updated_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Info(ctx, "info >>> %v", info)
+
+	if info.MatchedCount == 0 {
+		return entity.VerificationCodeInvalid
+	}
+
+	return nil
+}
+
+// SuspendMemberData replace the suspension of an already suspended member
+func (r GatewayApiBaseApp) SuspendMemberData(ctx context.Context, req entity.SuspendMemberReq) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	_, err := r.FindOneMemberDataById(ctx, req.MemberId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Local().UTC()
+	info, err := r.MongoWithTransactionImpl.UpdateByCustomId(ctx, r.database, entity.CollectionMember, req.MemberId, bson.M{
+		"is_suspend":      true,
+		"suspend_reason":  req.Reason,
+		"suspended_by":    req.SuspendedBy,
+		"suspended_at":    now,
+		"suspended_until": req.SuspendedUntil,
+		"updated_at":      now,
+	})
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindOneMemberDataById(ctx, req.MemberId)
+}
+
+// UnsuspendMemberData return MemberNotSuspended when the member is not suspended. With endedBefore
+// only a suspension whose suspended_until is before it is lifted, so a suspension extended in the meantime is kept
+func (r GatewayApiBaseApp) UnsuspendMemberData(ctx context.Context, id string, endedBefore *time.Time) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	filter := bson.M{"id": id, "is_suspend": true}
+	if endedBefore != nil {
+		filter["suspended_until"] = bson.M{"$lte": endedBefore}
+	}
+
+	coll := r.getMemberCollection()
+	info, err := coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+		"is_suspend":      false,
+		"suspend_reason":  "",
+		"suspended_by":    "",
+		"suspended_at":    nil,
+		"suspended_until": nil,
+		"updated_at":      time.Now().Local().UTC(),
+	}})
+	if err != nil {
+		return nil, err
+	}
+	log.Info(ctx, "info >>> %v", info)
+
+	if info.MatchedCount == 0 {
+		return nil, entity.MemberNotSuspended
+	}
+
+	return r.FindOneMemberDataById(ctx, id)
+}
+
+// FindAllMemberDataSuspensionEnded return the suspended members whose suspended_until has passed
+func (r GatewayApiBaseApp) FindAllMemberDataSuspensionEnded(ctx context.Context, now time.Time) ([]*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.MemberDataShown{}
+
+	coll := r.getMemberCollection()
+	cursor, err := coll.Find(ctx, notDeletedMember(bson.M{
+		"is_suspend":      true,
+		"suspended_until": bson.M{"$ne": nil, "$lte": now},
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+// SoftDeleteMemberData only set deleted_at, the member is hidden from every query until it is restored
+func (r GatewayApiBaseApp) SoftDeleteMemberData(ctx context.Context, id string, deletedBy string) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	now := time.Now().Local().UTC()
+
+	coll := r.getMemberCollection()
+	info, err := coll.UpdateOne(ctx, notDeletedMember(bson.M{"id": id}), bson.M{"$set": bson.M{
+		"deleted_at": now,
+		"deleted_by": deletedBy,
+		"updated_at": now,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	log.Info(ctx, "info >>> %v", info)
+	if info.MatchedCount == 0 {
+		return nil, entity.MemberNotFound
+	}
+
+	return r.FindOneMemberDataByIdWithDeleted(ctx, id)
+}
+
+// RestoreMemberData return MemberNotDeleted when the member is not soft deleted
+func (r GatewayApiBaseApp) RestoreMemberData(ctx context.Context, id string) (*entity.MemberDataShown, error) {
+	log.Info(ctx, "called")
+
+	coll := r.getMemberCollection()
+	info, err := coll.UpdateOne(ctx, bson.M{"id": id, "deleted_at": bson.M{"$ne": nil}}, bson.M{"$set": bson.M{
+		"deleted_at": nil,
+		"deleted_by": "",
+		"updated_at": time.Now().Local().UTC(),
+	}})
+	if err != nil {
+		return nil, err
 	}
-	if obj.TokenBroadcast != "" {
-		resultMemberDataShown.TokenBroadcast = obj.TokenBroadcast
+	log.Info(ctx, "info >>> %v", info)
+	if info.MatchedCount == 0 {
+		return nil, entity.MemberNotDeleted
+	}
+
+	return r.FindOneMemberDataById(ctx, id)
+}
+
+// PurgeMemberData remove the member and its documents in memberCollectionsByMemberId.
+// The audit events are kept, removing them would break the chain
+func (r GatewayApiBaseApp) PurgeMemberData(ctx context.Context, id string) error {
+	log.Info(ctx, "called")
+
+	database := r.MongoWithTransactionImpl.MongoClient.Database(r.database)
+	for _, collection := range memberCollectionsByMemberId {
+		info, err := database.Collection(collection).DeleteMany(ctx, bson.M{"id_member": id})
+		if err != nil {
+			return err
+		}
+		log.Info(ctx, "%s >>> %v", collection, info)
+	}
+
+	info, err := r.MongoWithTransactionImpl.DeleteByCustomId(ctx, r.database, entity.CollectionMember, id)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+func (r GatewayApiBaseApp) rehashMemberPassword(ctx context.Context, id string, plainPassword string) {
+	encryptPassword, err := r.EncryptPassword(ctx, plainPassword)
+	if err != nil {
+		log.Error(ctx, err.Error())
+		return
+	}
+
+	info, err := r.MongoWithTransactionImpl.UpdateByCustomId(ctx, r.database, entity.CollectionMember, id, bson.M{"password": encryptPassword})
+	if err != nil {
+		log.Error(ctx, err.Error())
+		return
 	}
-	return r.UpdateMemberData(ctx, *resultMemberDataShown)
+	log.Info(ctx, "rehash password >>> %v", info)
 }
 
 const DataRegistraionHasTaken domerror.ErrorType = "ER1006 data registration has been taken"
+const InvalidUsernameOrPassword domerror.ErrorType = "ER1007 invalid username or password"
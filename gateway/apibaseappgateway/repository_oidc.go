@@ -0,0 +1,120 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OidcStateRepo interface {
+	CreateOidcState(ctx context.Context, obj entity.OidcStateData) error
+	UseOidcState(ctx context.Context, id string) (*entity.OidcStateData, error)
+}
+
+type MemberIdentityRepo interface {
+	FindOneMemberIdentityById(ctx context.Context, id string) (*entity.MemberIdentityData, error)
+	CreateMemberIdentity(ctx context.Context, obj entity.MemberIdentityData) error
+	TouchMemberIdentity(ctx context.Context, id string) error
+}
+
+type oidcStateCollection struct {
+	*mongo.Collection
+}
+
+type memberIdentityCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getOidcStateCollection() oidcStateCollection {
+	return oidcStateCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionOidcState),
+	}
+}
+
+func (r GatewayApiBaseApp) getMemberIdentityCollection() memberIdentityCollection {
+	return memberIdentityCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionMemberIdentity),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareOidcCollection() {
+	stateColl := r.getOidcStateCollection()
+	identityColl := r.getMemberIdentityCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(stateColl.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedExpireAt(stateColl.Collection, "expired_at")
+	r.MongoWithTransactionImpl.CreateIndexedUnique(identityColl.Collection, "id")
+}
+
+func (r GatewayApiBaseApp) CreateOidcState(ctx context.Context, obj entity.OidcStateData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getOidcStateCollection()
+
+	info, err := coll.InsertOne(ctx, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// UseOidcState remove the state atomically, so a callback can only be redeemed once
+func (r GatewayApiBaseApp) UseOidcState(ctx context.Context, id string) (*entity.OidcStateData, error) {
+	log.Info(ctx, "called")
+
+	var resultOidcState entity.OidcStateData
+
+	coll := r.getOidcStateCollection()
+	err := coll.FindOneAndDelete(ctx, bson.M{"id": id, "expired_at": bson.M{"$gt": time.Now()}}).Decode(&resultOidcState)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.OidcStateInvalid
+		}
+		return nil, err
+	}
+
+	return &resultOidcState, nil
+}
+
+// FindOneMemberIdentityById return nil without error when the identity is not linked yet
+func (r GatewayApiBaseApp) FindOneMemberIdentityById(ctx context.Context, id string) (*entity.MemberIdentityData, error) {
+	log.Info(ctx, "called")
+
+	var resultMemberIdentity entity.MemberIdentityData
+
+	coll := r.getMemberIdentityCollection()
+	err := coll.FindOne(ctx, bson.M{"id": id}).Decode(&resultMemberIdentity)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &resultMemberIdentity, nil
+}
+
+func (r GatewayApiBaseApp) CreateMemberIdentity(ctx context.Context, obj entity.MemberIdentityData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getMemberIdentityCollection()
+
+	info, err := coll.InsertOne(ctx, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+func (r GatewayApiBaseApp) TouchMemberIdentity(ctx context.Context, id string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getMemberIdentityCollection()
+
+	info, err := coll.UpdateOne(ctx, bson.M{"id": id}, bson.M{"$set": bson.M{"last_login_at": time.Now()}})
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
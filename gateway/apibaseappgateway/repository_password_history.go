@@ -0,0 +1,113 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordHistoryRepo interface {
+	FindOnePasswordHistoryByMemberId(ctx context.Context, memberId string) (*entity.PasswordHistoryData, error)
+	AddPasswordHistory(ctx context.Context, memberId string, hashedPassword string) error
+}
+
+type passwordHistoryCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getPasswordHistoryCollection() passwordHistoryCollection {
+	return passwordHistoryCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionPasswordHistory),
+	}
+}
+
+func (r GatewayApiBaseApp) preparePasswordHistoryCollection() {
+	coll := r.getPasswordHistoryCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+}
+
+// FindOnePasswordHistoryByMemberId return an empty history for a member without one
+func (r GatewayApiBaseApp) FindOnePasswordHistoryByMemberId(ctx context.Context, memberId string) (*entity.PasswordHistoryData, error) {
+	log.Info(ctx, "called")
+
+	var resultPasswordHistory entity.PasswordHistoryData
+
+	coll := r.getPasswordHistoryCollection()
+	err := coll.FindOne(ctx, bson.M{"id": memberId}).Decode(&resultPasswordHistory)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &entity.PasswordHistoryData{ID: memberId, MemberId: memberId}, nil
+		}
+		return nil, err
+	}
+
+	return &resultPasswordHistory, nil
+}
+
+// AddPasswordHistory put the hash first and keep the longest history_size of the policies
+func (r GatewayApiBaseApp) AddPasswordHistory(ctx context.Context, memberId string, hashedPassword string) error {
+	log.Info(ctx, "called")
+
+	if r.passwordHistoryLimit == 0 {
+		return nil
+	}
+
+	coll := r.getPasswordHistoryCollection()
+
+	info, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": memberId},
+		bson.M{
+			"$push": bson.M{"hashes": bson.M{
+				"$each":     bson.A{hashedPassword},
+				"$position": 0,
+				"$slice":    r.passwordHistoryLimit,
+			}},
+			"$set":         bson.M{"updated_at": time.Now()},
+			"$setOnInsert": bson.M{"id_member": memberId},
+		},
+		options.Update().SetUpsert(true),
+	)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// AddManyPasswordHistory start the history of new members in a single bulk write
+func (r GatewayApiBaseApp) AddManyPasswordHistory(ctx context.Context, objs []entity.MemberData) error {
+	log.Info(ctx, "called")
+
+	if r.passwordHistoryLimit == 0 || len(objs) == 0 {
+		return nil
+	}
+
+	coll := r.getPasswordHistoryCollection()
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(objs))
+	for _, obj := range objs {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"id": obj.ID.String()}).
+			SetUpdate(bson.M{
+				"$push": bson.M{"hashes": bson.M{
+					"$each":     bson.A{obj.Password},
+					"$position": 0,
+					"$slice":    r.passwordHistoryLimit,
+				}},
+				"$set":         bson.M{"updated_at": now},
+				"$setOnInsert": bson.M{"id_member": obj.ID.String()},
+			}).
+			SetUpsert(true))
+	}
+
+	info, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
@@ -0,0 +1,103 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordResetRepo interface {
+	CreatePasswordReset(ctx context.Context, obj entity.PasswordResetData) error
+	FindOnePasswordReset(ctx context.Context, id string) (*entity.PasswordResetData, error)
+	UsePasswordReset(ctx context.Context, id string) (*entity.PasswordResetData, error)
+	DeletePasswordResetByMemberId(ctx context.Context, memberId string) error
+}
+
+type passwordResetCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getPasswordResetCollection() passwordResetCollection {
+	return passwordResetCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionPasswordReset),
+	}
+}
+
+func (r GatewayApiBaseApp) preparePasswordResetCollection() {
+	coll := r.getPasswordResetCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedExpireAt(coll.Collection, "expired_at")
+}
+
+func (r GatewayApiBaseApp) CreatePasswordReset(ctx context.Context, obj entity.PasswordResetData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getPasswordResetCollection()
+
+	info, err := coll.InsertOne(ctx, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// UsePasswordReset mark the token as used atomically, so it can only be used once
+// FindOnePasswordReset return a token which can still be used, without using it
+func (r GatewayApiBaseApp) FindOnePasswordReset(ctx context.Context, id string) (*entity.PasswordResetData, error) {
+	log.Info(ctx, "called")
+
+	var resultPasswordReset entity.PasswordResetData
+
+	coll := r.getPasswordResetCollection()
+	err := coll.FindOne(ctx, bson.M{"id": id, "is_used": false, "expired_at": bson.M{"$gt": time.Now()}}).Decode(&resultPasswordReset)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.PasswordResetTokenInvalid
+		}
+		return nil, err
+	}
+
+	return &resultPasswordReset, nil
+}
+
+func (r GatewayApiBaseApp) UsePasswordReset(ctx context.Context, id string) (*entity.PasswordResetData, error) {
+	log.Info(ctx, "called")
+
+	var resultPasswordReset entity.PasswordResetData
+
+	coll := r.getPasswordResetCollection()
+
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := coll.FindOneAndUpdate(
+		ctx,
+		bson.M{"id": id, "is_used": false, "expired_at": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{"is_used": true, "used_at": now}},
+		opts,
+	).Decode(&resultPasswordReset)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.PasswordResetTokenInvalid
+		}
+		return nil, err
+	}
+
+	return &resultPasswordReset, nil
+}
+
+// DeletePasswordResetByMemberId invalidate every token which is not used yet
+func (r GatewayApiBaseApp) DeletePasswordResetByMemberId(ctx context.Context, memberId string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getPasswordResetCollection()
+
+	info, err := coll.DeleteMany(ctx, bson.M{"id_member": memberId, "is_used": false})
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
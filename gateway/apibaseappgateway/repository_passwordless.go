@@ -0,0 +1,137 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordlessChallengeRepo interface {
+	SavePasswordlessChallenge(ctx context.Context, obj entity.PasswordlessChallengeData) error
+	FindOnePasswordlessChallengeById(ctx context.Context, id string) (*entity.PasswordlessChallengeData, error)
+	FindOnePasswordlessChallengeByMemberId(ctx context.Context, memberId string) (*entity.PasswordlessChallengeData, error)
+	AttemptPasswordlessChallengeCode(ctx context.Context, memberId string, maxAttempt int, now time.Time) (*entity.PasswordlessChallengeData, error)
+	UsePasswordlessChallenge(ctx context.Context, id string) error
+}
+
+type passwordlessChallengeCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getPasswordlessChallengeCollection() passwordlessChallengeCollection {
+	return passwordlessChallengeCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionPasswordlessChallenge),
+	}
+}
+
+func (r GatewayApiBaseApp) preparePasswordlessChallengeCollection() {
+	coll := r.getPasswordlessChallengeCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id_member")
+	r.MongoWithTransactionImpl.CreateIndexedExpireAt(coll.Collection, "expired_at")
+}
+
+// SavePasswordlessChallenge replace the challenge of the member, only the latest link or code is valid
+func (r GatewayApiBaseApp) SavePasswordlessChallenge(ctx context.Context, obj entity.PasswordlessChallengeData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getPasswordlessChallengeCollection()
+
+	info, err := coll.ReplaceOne(ctx, bson.M{"id_member": obj.MemberId}, obj, options.Replace().SetUpsert(true))
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// FindOnePasswordlessChallengeById return PasswordlessChallengeInvalid when the challenge is used or removed
+func (r GatewayApiBaseApp) FindOnePasswordlessChallengeById(ctx context.Context, id string) (*entity.PasswordlessChallengeData, error) {
+	log.Info(ctx, "called")
+
+	var resultChallenge entity.PasswordlessChallengeData
+
+	coll := r.getPasswordlessChallengeCollection()
+	err := coll.FindOne(ctx, bson.M{"id": id}).Decode(&resultChallenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.PasswordlessChallengeInvalid
+		}
+		return nil, err
+	}
+
+	return &resultChallenge, nil
+}
+
+// FindOnePasswordlessChallengeByMemberId return PasswordlessChallengeInvalid when the member has no challenge
+func (r GatewayApiBaseApp) FindOnePasswordlessChallengeByMemberId(ctx context.Context, memberId string) (*entity.PasswordlessChallengeData, error) {
+	log.Info(ctx, "called")
+
+	var resultChallenge entity.PasswordlessChallengeData
+
+	coll := r.getPasswordlessChallengeCollection()
+	err := coll.FindOne(ctx, bson.M{"id_member": memberId}).Decode(&resultChallenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.PasswordlessChallengeInvalid
+		}
+		return nil, err
+	}
+
+	return &resultChallenge, nil
+}
+
+// AttemptPasswordlessChallengeCode count an attempt before the code is compared, only a challenge
+// not expired and below maxAttempt is counted so concurrent guesses can not exceed it.
+// The challenge is returned as it was before the attempt, PasswordlessChallengeInvalid when none match
+func (r GatewayApiBaseApp) AttemptPasswordlessChallengeCode(ctx context.Context, memberId string, maxAttempt int, now time.Time) (*entity.PasswordlessChallengeData, error) {
+	log.Info(ctx, "called")
+
+	var resultChallenge entity.PasswordlessChallengeData
+
+	coll := r.getPasswordlessChallengeCollection()
+
+	// a magic link is never guessed by code, its challenge is not counted
+	filter := bson.M{
+		"id_member":  memberId,
+		"channel":    entity.VerificationChannelPhone,
+		"expired_at": bson.M{"$gt": now},
+	}
+	if maxAttempt > 0 {
+		filter["failed_attempt"] = bson.M{"$lt": maxAttempt}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	err := coll.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{"failed_attempt": 1}}, opts).Decode(&resultChallenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.PasswordlessChallengeInvalid
+		}
+		return nil, err
+	}
+
+	return &resultChallenge, nil
+}
+
+// UsePasswordlessChallenge remove the challenge atomically, so the link or code can only be used once
+func (r GatewayApiBaseApp) UsePasswordlessChallenge(ctx context.Context, id string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getPasswordlessChallengeCollection()
+
+	info, err := coll.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	log.Info(ctx, "info >>> %v", info)
+
+	if info.DeletedCount == 0 {
+		return entity.PasswordlessChallengeInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RefreshTokenRepo interface {
+	CreateRefreshToken(ctx context.Context, obj entity.RefreshTokenData) error
+	UseRefreshToken(ctx context.Context, id string) (*entity.RefreshTokenData, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyId string) error
+	RevokeRefreshTokenByMemberId(ctx context.Context, memberId string) error
+}
+
+type refreshTokenCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getRefreshTokenCollection() refreshTokenCollection {
+	return refreshTokenCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionRefreshToken),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareRefreshTokenCollection() {
+	coll := r.getRefreshTokenCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedExpireAt(coll.Collection, "expired_at")
+}
+
+func (r GatewayApiBaseApp) CreateRefreshToken(ctx context.Context, obj entity.RefreshTokenData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getRefreshTokenCollection()
+
+	info, err := coll.InsertOne(ctx, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// UseRefreshToken atomically mark the refresh token as used and return its state before the update,
+// so the caller can detect that the token had already been used
+func (r GatewayApiBaseApp) UseRefreshToken(ctx context.Context, id string) (*entity.RefreshTokenData, error) {
+	log.Info(ctx, "called")
+
+	var resultRefreshToken entity.RefreshTokenData
+
+	coll := r.getRefreshTokenCollection()
+
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	err := coll.FindOneAndUpdate(
+		ctx,
+		bson.M{"id": id},
+		bson.M{"$set": bson.M{"is_used": true, "used_at": now}},
+		opts,
+	).Decode(&resultRefreshToken)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.RefreshTokenInvalid
+		}
+		return nil, err
+	}
+
+	return &resultRefreshToken, nil
+}
+
+func (r GatewayApiBaseApp) RevokeRefreshTokenFamily(ctx context.Context, familyId string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getRefreshTokenCollection()
+
+	now := time.Now()
+	info, err := coll.UpdateMany(
+		ctx,
+		bson.M{"id_family": familyId, "is_revoked": false},
+		bson.M{"$set": bson.M{"is_revoked": true, "revoked_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Info(ctx, "revoked >>> %v", info.ModifiedCount)
+
+	return nil
+}
+
+func (r GatewayApiBaseApp) RevokeRefreshTokenByMemberId(ctx context.Context, memberId string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getRefreshTokenCollection()
+
+	now := time.Now()
+	info, err := coll.UpdateMany(
+		ctx,
+		bson.M{"id_member": memberId, "is_revoked": false},
+		bson.M{"$set": bson.M{"is_revoked": true, "revoked_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Info(ctx, "revoked >>> %v", info.ModifiedCount)
+
+	return nil
+}
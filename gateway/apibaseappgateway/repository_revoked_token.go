@@ -0,0 +1,80 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RevokedTokenRepo interface {
+	RevokeToken(ctx context.Context, obj entity.RevokedTokenData) error
+	ConsumeToken(ctx context.Context, obj entity.RevokedTokenData) error
+	IsTokenRevoked(ctx context.Context, req entity.CheckRevokedTokenReq) (bool, error)
+}
+
+type revokedTokenCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getRevokedTokenCollection() revokedTokenCollection {
+	return revokedTokenCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionRevokedToken),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareRevokedTokenCollection() {
+	coll := r.getRevokedTokenCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedExpireAt(coll.Collection, "expired_at")
+}
+
+func (r GatewayApiBaseApp) RevokeToken(ctx context.Context, obj entity.RevokedTokenData) error {
+	log.Info(ctx, "called")
+
+	info, err := r.MongoWithTransactionImpl.SaveOrUpdateByCustomId(ctx, r.database, entity.CollectionRevokedToken, obj.ID, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// ConsumeToken revoke a single use token, the unique id let only the first of concurrent calls insert it
+// and the others get TokenHasBeenRevoked
+func (r GatewayApiBaseApp) ConsumeToken(ctx context.Context, obj entity.RevokedTokenData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getRevokedTokenCollection()
+
+	info, err := coll.InsertOne(ctx, obj)
+	log.Info(ctx, "info >>> %v", info)
+	if mongo.IsDuplicateKeyError(err) {
+		return entity.TokenHasBeenRevoked
+	}
+
+	return err
+}
+
+func (r GatewayApiBaseApp) IsTokenRevoked(ctx context.Context, req entity.CheckRevokedTokenReq) (bool, error) {
+	log.Info(ctx, "called")
+
+	coll := r.getRevokedTokenCollection()
+
+	criteria := bson.M{"$or": []bson.M{
+		{"id": fmt.Sprintf("%s:%s", entity.RevokedTokenKindToken, req.TokenId)},
+		{
+			"id":            fmt.Sprintf("%s:%s", entity.RevokedTokenKindMember, req.MemberId),
+			"issued_before": bson.M{"$gt": req.IssuedAt},
+		},
+	}}
+
+	count, err := coll.CountDocuments(ctx, criteria)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
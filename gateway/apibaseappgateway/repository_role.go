@@ -0,0 +1,251 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RoleRepo interface {
+	CreateRole(ctx context.Context, obj entity.RoleData) error
+	FindOneRoleById(ctx context.Context, id string) (*entity.RoleData, error)
+	FindAllRoleByIds(ctx context.Context, ids []string) ([]*entity.RoleData, error)
+	FindAllRole(ctx context.Context, req entity.BaseReqFind) ([]*entity.RoleData, int64, error)
+	UpdateRole(ctx context.Context, obj entity.RoleData) (*entity.RoleData, error)
+	DeleteRole(ctx context.Context, id string) error
+	FindAllPermission(ctx context.Context) ([]*entity.PermissionData, error)
+	FindAllPermissionByIds(ctx context.Context, ids []string) ([]*entity.PermissionData, error)
+}
+
+type roleCollection struct {
+	*mongo.Collection
+}
+
+type permissionCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getRoleCollection() roleCollection {
+	return roleCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionRole),
+	}
+}
+
+func (r GatewayApiBaseApp) getPermissionCollection() permissionCollection {
+	return permissionCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionPermission),
+	}
+}
+
+// prepareRoleCollection register the default permissions and roles,
+// and give the superadmin role to the bootstrap member so the roles can be managed at runtime
+func (r GatewayApiBaseApp) prepareRoleCollection(superadminUsername string) {
+	ctx := context.Background()
+
+	roleColl := r.getRoleCollection()
+	permissionColl := r.getPermissionCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(roleColl.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedUnique(permissionColl.Collection, "id")
+
+	for _, permission := range entity.DefaultPermissions {
+		_, err := permissionColl.UpdateOne(
+			ctx,
+			bson.M{"id": permission.ID},
+			bson.M{"$set": permission},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	for _, req := range entity.DefaultRoles {
+		role, err := entity.NewRoleData(req)
+		if err != nil {
+			panic(err)
+		}
+		_, err = roleColl.UpdateOne(
+			ctx,
+			bson.M{"id": role.ID},
+			bson.M{"$setOnInsert": role},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	if superadminUsername == "" {
+		return
+	}
+
+	_, err := r.getMemberCollection().UpdateOne(
+		ctx,
+		bson.M{"username": superadminUsername},
+		bson.M{"$addToSet": bson.M{"roles": entity.RoleSuperadmin}},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (r GatewayApiBaseApp) CreateRole(ctx context.Context, obj entity.RoleData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getRoleCollection()
+
+	count, err := coll.CountDocuments(ctx, bson.M{"id": obj.ID})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return entity.RoleAlreadyExist.Var(obj.ID)
+	}
+
+	info, err := coll.InsertOne(ctx, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+func (r GatewayApiBaseApp) FindOneRoleById(ctx context.Context, id string) (*entity.RoleData, error) {
+	log.Info(ctx, "called")
+
+	var resultRole entity.RoleData
+
+	coll := r.getRoleCollection()
+	err := coll.FindOne(ctx, bson.M{"id": id}).Decode(&resultRole)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.RoleNotFound.Var(id)
+		}
+		return nil, err
+	}
+
+	return &resultRole, nil
+}
+
+func (r GatewayApiBaseApp) FindAllRoleByIds(ctx context.Context, ids []string) ([]*entity.RoleData, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.RoleData{}
+
+	coll := r.getRoleCollection()
+	cursor, err := coll.Find(ctx, bson.M{"id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+func (r GatewayApiBaseApp) FindAllRole(ctx context.Context, req entity.BaseReqFind) ([]*entity.RoleData, int64, error) {
+	log.Info(ctx, "called")
+
+	var objs []*entity.RoleData
+
+	coll := r.getRoleCollection()
+
+	criteria := bson.M{}
+	findData, _ := req.Value.(entity.RoleDataFind)
+	if findData.Name != "" {
+		criteria = bson.M{"name": primitive.Regex{Pattern: findData.Name, Options: "i"}}
+	}
+
+	findOpts := gateway.BaseReqFindToOptOption(req)
+
+	cursor, err := coll.Find(ctx, criteria, &findOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, 0, err
+	}
+
+	count, err := coll.CountDocuments(ctx, criteria)
+
+	return objs, count, err
+}
+
+func (r GatewayApiBaseApp) UpdateRole(ctx context.Context, obj entity.RoleData) (*entity.RoleData, error) {
+	log.Info(ctx, "called")
+
+	obj.UpdatedAt = time.Now()
+
+	info, err := r.MongoWithTransactionImpl.UpdateByCustomId(ctx, r.database, entity.CollectionRole, obj.ID, obj)
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
+
+// DeleteRole also remove the role from every member holding it
+func (r GatewayApiBaseApp) DeleteRole(ctx context.Context, id string) error {
+	log.Info(ctx, "called")
+
+	info, err := r.MongoWithTransactionImpl.DeleteByCustomId(ctx, r.database, entity.CollectionRole, id)
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getMemberCollection().UpdateMany(
+		ctx,
+		bson.M{"roles": id},
+		bson.M{"$pull": bson.M{"roles": id}},
+	)
+
+	return err
+}
+
+func (r GatewayApiBaseApp) FindAllPermission(ctx context.Context) ([]*entity.PermissionData, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.PermissionData{}
+
+	coll := r.getPermissionCollection()
+	findOpts := options.Find().SetSort(bson.D{{Key: "id", Value: 1}})
+	cursor, err := coll.Find(ctx, bson.M{}, findOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+func (r GatewayApiBaseApp) FindAllPermissionByIds(ctx context.Context, ids []string) ([]*entity.PermissionData, error) {
+	log.Info(ctx, "called")
+
+	objs := []*entity.PermissionData{}
+
+	coll := r.getPermissionCollection()
+	cursor, err := coll.Find(ctx, bson.M{"id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
@@ -0,0 +1,176 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sessionLastSeenInterval avoid writing the session on every single request
+const sessionLastSeenInterval = time.Minute
+
+type SessionRepo interface {
+	CreateSession(ctx context.Context, obj entity.SessionData) error
+	FindOneSessionById(ctx context.Context, id string) (*entity.SessionData, error)
+	FindAllActiveSessionByMemberId(ctx context.Context, memberId string) ([]*entity.SessionData, error)
+	TouchSession(ctx context.Context, id string) error
+	ExtendSession(ctx context.Context, id string, expiredAt time.Time) error
+	UpdateSessionAuthentication(ctx context.Context, id string, authTime time.Time, amr []string) error
+	RevokeSession(ctx context.Context, id string) error
+	RevokeSessionByMemberId(ctx context.Context, memberId string) error
+}
+
+type sessionCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getSessionCollection() sessionCollection {
+	return sessionCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionSession),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareSessionCollection() {
+	coll := r.getSessionCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedExpireAt(coll.Collection, "expired_at")
+}
+
+func (r GatewayApiBaseApp) CreateSession(ctx context.Context, obj entity.SessionData) error {
+	log.Info(ctx, "called")
+
+	coll := r.getSessionCollection()
+
+	info, err := coll.InsertOne(ctx, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+func (r GatewayApiBaseApp) FindOneSessionById(ctx context.Context, id string) (*entity.SessionData, error) {
+	log.Info(ctx, "called")
+
+	var resultSession entity.SessionData
+
+	coll := r.getSessionCollection()
+	err := coll.FindOne(ctx, bson.M{"id": id}).Decode(&resultSession)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.SessionNotFound
+		}
+		return nil, err
+	}
+
+	return &resultSession, nil
+}
+
+func (r GatewayApiBaseApp) FindAllActiveSessionByMemberId(ctx context.Context, memberId string) ([]*entity.SessionData, error) {
+	log.Info(ctx, "called")
+
+	var objs []*entity.SessionData
+
+	coll := r.getSessionCollection()
+
+	criteria := bson.M{
+		"id_member":  memberId,
+		"is_revoked": false,
+		"expired_at": bson.M{"$gt": time.Now()},
+	}
+	findOpts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := coll.Find(ctx, criteria, findOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &objs); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+func (r GatewayApiBaseApp) TouchSession(ctx context.Context, id string) error {
+	coll := r.getSessionCollection()
+
+	now := time.Now()
+	_, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": id, "last_seen_at": bson.M{"$lt": now.Add(-sessionLastSeenInterval)}},
+		bson.M{"$set": bson.M{"last_seen_at": now}},
+	)
+
+	return err
+}
+
+func (r GatewayApiBaseApp) ExtendSession(ctx context.Context, id string, expiredAt time.Time) error {
+	log.Info(ctx, "called")
+
+	coll := r.getSessionCollection()
+
+	_, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": id},
+		bson.M{"$set": bson.M{"last_seen_at": time.Now(), "expired_at": expiredAt}},
+	)
+
+	return err
+}
+
+// UpdateSessionAuthentication store the last reauthentication, the next tokens of the session carry it
+func (r GatewayApiBaseApp) UpdateSessionAuthentication(ctx context.Context, id string, authTime time.Time, amr []string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getSessionCollection()
+
+	_, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": id, "is_revoked": false},
+		bson.M{"$set": bson.M{"auth_time": authTime, "amr": amr, "last_seen_at": authTime}},
+	)
+
+	return err
+}
+
+func (r GatewayApiBaseApp) RevokeSession(ctx context.Context, id string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getSessionCollection()
+
+	now := time.Now()
+	_, err := coll.UpdateOne(
+		ctx,
+		bson.M{"id": id, "is_revoked": false},
+		bson.M{"$set": bson.M{"is_revoked": true, "revoked_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.RevokeRefreshTokenFamily(ctx, id)
+}
+
+func (r GatewayApiBaseApp) RevokeSessionByMemberId(ctx context.Context, memberId string) error {
+	log.Info(ctx, "called")
+
+	coll := r.getSessionCollection()
+
+	now := time.Now()
+	info, err := coll.UpdateMany(
+		ctx,
+		bson.M{"id_member": memberId, "is_revoked": false},
+		bson.M{"$set": bson.M{"is_revoked": true, "revoked_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	log.Info(ctx, "revoked >>> %v", info.ModifiedCount)
+
+	return r.RevokeRefreshTokenByMemberId(ctx, memberId)
+}
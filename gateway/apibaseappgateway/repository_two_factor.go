@@ -0,0 +1,103 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TwoFactorRepo interface {
+	FindOneTwoFactorByMemberId(ctx context.Context, memberId string) (*entity.TwoFactorData, error)
+	SaveTwoFactor(ctx context.Context, obj entity.TwoFactorData) error
+	UseTwoFactorCode(ctx context.Context, obj entity.TwoFactorCodeUse) error
+	DeleteTwoFactor(ctx context.Context, memberId string) error
+}
+
+type twoFactorCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getTwoFactorCollection() twoFactorCollection {
+	return twoFactorCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionTwoFactor),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareTwoFactorCollection() {
+	coll := r.getTwoFactorCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+}
+
+// FindOneTwoFactorByMemberId return TwoFactorNotEnrolled when the member has never enrolled
+func (r GatewayApiBaseApp) FindOneTwoFactorByMemberId(ctx context.Context, memberId string) (*entity.TwoFactorData, error) {
+	log.Info(ctx, "called")
+
+	var resultTwoFactor entity.TwoFactorData
+
+	coll := r.getTwoFactorCollection()
+	err := coll.FindOne(ctx, bson.M{"id": memberId}).Decode(&resultTwoFactor)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.TwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+
+	return &resultTwoFactor, nil
+}
+
+func (r GatewayApiBaseApp) SaveTwoFactor(ctx context.Context, obj entity.TwoFactorData) error {
+	log.Info(ctx, "called")
+
+	info, err := r.MongoWithTransactionImpl.SaveOrUpdateByCustomId(ctx, r.database, entity.CollectionTwoFactor, obj.ID, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// UseTwoFactorCode consume the code atomically, so two requests with the same code can not both succeed
+func (r GatewayApiBaseApp) UseTwoFactorCode(ctx context.Context, obj entity.TwoFactorCodeUse) error {
+	log.Info(ctx, "called")
+
+	coll := r.getTwoFactorCollection()
+
+	criteria := bson.M{"id": obj.MemberId, "is_enabled": true}
+	update := bson.M{"updated_at": time.Now()}
+	var err error
+	var info *mongo.UpdateResult
+	if obj.RecoveryCodeHash != "" {
+		criteria["recovery_codes"] = obj.RecoveryCodeHash
+		info, err = coll.UpdateOne(ctx, criteria, bson.M{
+			"$set":  update,
+			"$pull": bson.M{"recovery_codes": obj.RecoveryCodeHash},
+		})
+	} else {
+		criteria["last_used_counter"] = bson.M{"$lt": obj.Counter}
+		update["last_used_counter"] = obj.Counter
+		info, err = coll.UpdateOne(ctx, criteria, bson.M{"$set": update})
+	}
+	log.Info(ctx, "info >>> %v", info)
+	if err != nil {
+		return err
+	}
+
+	if info.MatchedCount == 0 {
+		return entity.TwoFactorCodeInvalid
+	}
+
+	return nil
+}
+
+func (r GatewayApiBaseApp) DeleteTwoFactor(ctx context.Context, memberId string) error {
+	log.Info(ctx, "called")
+
+	info, err := r.MongoWithTransactionImpl.DeleteByCustomId(ctx, r.database, entity.CollectionTwoFactor, memberId)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
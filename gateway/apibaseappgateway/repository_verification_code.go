@@ -0,0 +1,94 @@
+package apibaseappgateway
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type VerificationCodeRepo interface {
+	FindOneVerificationCodeById(ctx context.Context, id string) (*entity.VerificationCodeData, error)
+	SaveVerificationCode(ctx context.Context, obj entity.VerificationCodeData) error
+	AttemptVerificationCode(ctx context.Context, id string, maxAttempt int, now time.Time) (*entity.VerificationCodeData, error)
+}
+
+type verificationCodeCollection struct {
+	*mongo.Collection
+}
+
+func (r GatewayApiBaseApp) getVerificationCodeCollection() verificationCodeCollection {
+	return verificationCodeCollection{
+		r.MongoWithTransactionImpl.MongoClient.Database(r.database).Collection(entity.CollectionVerificationCode),
+	}
+}
+
+func (r GatewayApiBaseApp) prepareVerificationCodeCollection() {
+	coll := r.getVerificationCodeCollection()
+
+	r.MongoWithTransactionImpl.CreateIndexedUnique(coll.Collection, "id")
+	r.MongoWithTransactionImpl.CreateIndexedExpireAt(coll.Collection, "expired_at")
+}
+
+// FindOneVerificationCodeById return VerificationCodeInvalid when there is no code,
+// the document the TTL monitor has not removed yet is skipped
+func (r GatewayApiBaseApp) FindOneVerificationCodeById(ctx context.Context, id string) (*entity.VerificationCodeData, error) {
+	log.Info(ctx, "called")
+
+	var resultVerificationCode entity.VerificationCodeData
+
+	coll := r.getVerificationCodeCollection()
+	err := coll.FindOne(ctx, bson.M{"id": id, "expired_at": bson.M{"$gt": time.Now()}}).Decode(&resultVerificationCode)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.VerificationCodeInvalid
+		}
+		return nil, err
+	}
+
+	return &resultVerificationCode, nil
+}
+
+func (r GatewayApiBaseApp) SaveVerificationCode(ctx context.Context, obj entity.VerificationCodeData) error {
+	log.Info(ctx, "called")
+
+	info, err := r.MongoWithTransactionImpl.SaveOrUpdateByCustomId(ctx, r.database, entity.CollectionVerificationCode, obj.ID, obj)
+	log.Info(ctx, "info >>> %v", info)
+
+	return err
+}
+
+// AttemptVerificationCode count an attempt before the code is compared, only a code not used, not expired
+// and below maxAttempt is counted so concurrent guesses can not exceed it.
+// The code is returned as it was before the attempt, VerificationCodeInvalid when none match
+func (r GatewayApiBaseApp) AttemptVerificationCode(ctx context.Context, id string, maxAttempt int, now time.Time) (*entity.VerificationCodeData, error) {
+	log.Info(ctx, "called")
+
+	var resultVerificationCode entity.VerificationCodeData
+
+	coll := r.getVerificationCodeCollection()
+
+	filter := bson.M{
+		"id":              id,
+		"code_hash":       bson.M{"$ne": ""},
+		"code_expired_at": bson.M{"$gt": now},
+	}
+	if maxAttempt > 0 {
+		filter["failed_attempt"] = bson.M{"$lt": maxAttempt}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	err := coll.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{"failed_attempt": 1}}, opts).Decode(&resultVerificationCode)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.VerificationCodeInvalid
+		}
+		return nil, err
+	}
+
+	return &resultVerificationCode, nil
+}
@@ -33,7 +33,7 @@ func NewPostgresDefault(config cfg.Config) (db *gorm.DB) {
 		panic(fmt.Errorf("user or password ord databaseName is empty"))
 	}
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s dbname=%s password=%s sslmode=%v", config.GetString("database.postgresql.host"), config.GetString("database.postgresql.port"), config.GetString("database.postgresql.username"), config.GetString("database.postgresql.database"), config.GetString("database.postgresql.password"), false)
+	dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%v", config.GetString("database.postgresql.host"), config.GetString("database.postgresql.port"), config.GetString("database.postgresql.username"), config.GetString("database.postgresql.database"), config.GetString("database.postgresql.password"), false)
 
 	loggerMode := logger.Silent
 
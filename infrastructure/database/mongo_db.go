@@ -44,8 +44,33 @@ func (r *MongoWithTransactionImpl) CreateIndexedTTL(coll *mongo.Collection, date
 	}
 }
 
+// CreateIndexedExpireAt remove each document once the time stored in dateField has passed
+func (r *MongoWithTransactionImpl) CreateIndexedExpireAt(coll *mongo.Collection, dateField string) {
+	index := mongo.IndexModel{
+		Keys:    bson.M{dateField: 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	_, err := coll.Indexes().CreateOne(context.Background(), index)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (r *MongoWithTransactionImpl) CreateIndexedUnique(coll *mongo.Collection, field string) {
+	index := mongo.IndexModel{
+		Keys:    bson.M{field: 1},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := coll.Indexes().CreateOne(context.Background(), index)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func (r *MongoWithTransactionImpl) createCollection(coll *mongo.Collection, db *mongo.Database) {
-	createCmd := bson.D{{"create", coll.Name()}}
+	createCmd := bson.D{{Key: "create", Value: coll.Name()}}
 	res := db.RunCommand(context.Background(), createCmd)
 	err := res.Err()
 	if err != nil {
@@ -57,8 +82,8 @@ func (r *MongoWithTransactionImpl) SaveOrUpdate(ctx context.Context, databaseNam
 
 	coll := r.MongoClient.Database(databaseName).Collection(collectionName)
 
-	filter := bson.D{{"_id", id}}
-	update := bson.D{{"$set", data}}
+	filter := bson.D{{Key: "_id", Value: id}}
+	update := bson.D{{Key: "$set", Value: data}}
 	opts := options.Update().SetUpsert(true)
 
 	result, err := coll.UpdateOne(ctx, filter, update, opts)
@@ -73,8 +98,8 @@ func (r *MongoWithTransactionImpl) SaveOrUpdateByCustomId(ctx context.Context, d
 
 	coll := r.MongoClient.Database(databaseName).Collection(collectionName)
 
-	filter := bson.D{{"id", id}}
-	update := bson.D{{"$set", data}}
+	filter := bson.D{{Key: "id", Value: id}}
+	update := bson.D{{Key: "$set", Value: data}}
 	opts := options.Update().SetUpsert(true)
 
 	result, err := coll.UpdateOne(ctx, filter, update, opts)
@@ -89,8 +114,8 @@ func (r *MongoWithTransactionImpl) UpdateByCustomId(ctx context.Context, databas
 
 	coll := r.MongoClient.Database(databaseName).Collection(collectionName)
 
-	filter := bson.D{{"id", id}}
-	update := bson.D{{"$set", data}}
+	filter := bson.D{{Key: "id", Value: id}}
+	update := bson.D{{Key: "$set", Value: data}}
 
 	result, err := coll.UpdateOne(ctx, filter, update)
 	if err != nil {
@@ -104,9 +129,9 @@ func (r *MongoWithTransactionImpl) DeleteByCustomId(ctx context.Context, databas
 
 	coll := r.MongoClient.Database(databaseName).Collection(collectionName)
 
-	filter := bson.D{{"id", id}}
+	filter := bson.D{{Key: "id", Value: id}}
 	result, err := coll.DeleteOne(ctx, filter)
-	log.Info(ctx, "info >>>  ", result)
+	log.Info(ctx, "info >>>  %v", result)
 	if err != nil {
 		return nil, err
 	}
@@ -9,7 +9,7 @@ import (
 )
 
 func NewGinHTTPHandlerDefault(address string) GinHTTPHandler {
-	return NewGinHTTPHandler(address)
+	return NewGinHTTPHandler(address, nil)
 }
 
 // GinHTTPHandler will define basic HTTP configuration with gracefully shutdown
@@ -18,7 +18,9 @@ type GinHTTPHandler struct {
 	Router *gin.Engine
 }
 
-func NewGinHTTPHandler(address string) GinHTTPHandler {
+// NewGinHTTPHandler allow every origin without credentials when allowOrigins is empty, a browser only
+// send the cookies to a listed origin since the wildcard origin is refused with credentials
+func NewGinHTTPHandler(address string, allowOrigins []string) GinHTTPHandler {
 
 	router := gin.Default()
 
@@ -28,12 +30,16 @@ func NewGinHTTPHandler(address string) GinHTTPHandler {
 	})
 
 	// CORS
+	allowCredentials := len(allowOrigins) > 0
+	if !allowCredentials {
+		allowOrigins = []string{"*"}
+	}
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
+		AllowOrigins:     allowOrigins,
 		ExposeHeaders:    []string{"Data-Length", "Content-Length"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"},
-		AllowCredentials: true,
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "api-key"},
+		AllowCredentials: allowCredentials,
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "api-key", "X-Session-Mode", "X-CSRF-Token"},
 		MaxAge:           12 * time.Hour,
 	}))
 
@@ -0,0 +1,251 @@
+package jwtkey
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrSigningKeyNotFound = errors.New("signing key not found")
+	ErrKeyNotFound        = errors.New("verification key not found")
+)
+
+type (
+	// Config defines the keys used to sign and verify the access token.
+	Config struct {
+		// SigningKid is the kid of the key used to sign new token.
+		// Required when Keys is not empty.
+		SigningKid string `mapstructure:"signing_kid"`
+
+		// Keys are every key trusted for verification.
+		// Optional. When empty the token is signed with HS256 using Secret.
+		Keys []KeyConfig `mapstructure:"keys"`
+
+		// Secret is the HS256 shared secret, only used when Keys is empty.
+		Secret string `mapstructure:"-"`
+
+		// TokenLifetime is the longest lifetime of a signed token,
+		// a retired key stay valid for verification during this time.
+		TokenLifetime time.Duration `mapstructure:"-"`
+	}
+
+	// KeyConfig defines a single asymmetric key loaded from PEM files.
+	KeyConfig struct {
+		Kid string `mapstructure:"kid"`
+
+		// Algorithm is one of RS256, ES256 or EdDSA.
+		Algorithm string `mapstructure:"algorithm"`
+
+		// PrivateKeyFile is needed for the signing key only.
+		PrivateKeyFile string `mapstructure:"private_key_file"`
+
+		// PublicKeyFile is optional when PrivateKeyFile is set.
+		PublicKeyFile string `mapstructure:"public_key_file"`
+
+		// RetiredAt is the time the key stopped signing, RFC3339 format.
+		// Optional. The key is dropped once every token it signed has expired.
+		RetiredAt string `mapstructure:"retired_at"`
+	}
+)
+
+type Key struct {
+	Kid        string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.PrivateKey
+	PublicKey  crypto.PublicKey
+	// ValidUntil is zero for key that is not retired
+	ValidUntil time.Time
+}
+
+// KeySet hold every key trusted by the service, chosen by the kid header
+type KeySet struct {
+	signingKid string
+	keys       map[string]*Key
+}
+
+func LoadKeySet(config Config) (*KeySet, error) {
+	// legacy shared secret
+	if len(config.Keys) == 0 {
+		return NewSecretKeySet(config.Secret), nil
+	}
+
+	keySet := &KeySet{
+		signingKid: config.SigningKid,
+		keys:       map[string]*Key{},
+	}
+
+	for _, keyConfig := range config.Keys {
+		key, err := loadKey(keyConfig, config.TokenLifetime)
+		if err != nil {
+			return nil, fmt.Errorf("load key %s : %w", keyConfig.Kid, err)
+		}
+		keySet.keys[key.Kid] = key
+	}
+
+	signingKey, exist := keySet.keys[config.SigningKid]
+	if !exist || signingKey.PrivateKey == nil || !signingKey.ValidUntil.IsZero() {
+		return nil, ErrSigningKeyNotFound
+	}
+
+	return keySet, nil
+}
+
+// NewKeySet return a key set signing with a single key created at runtime
+func NewKeySet(signingKey *Key) *KeySet {
+	return &KeySet{
+		signingKid: signingKey.Kid,
+		keys:       map[string]*Key{signingKey.Kid: signingKey},
+	}
+}
+
+// NewSecretKeySet return a key set holding a single HS256 key without kid,
+// it is never published in the JWKS
+func NewSecretKeySet(secret string) *KeySet {
+	return &KeySet{
+		keys: map[string]*Key{
+			"": {
+				Method:     jwt.SigningMethodHS256,
+				PrivateKey: []byte(secret),
+				PublicKey:  []byte(secret),
+			},
+		},
+	}
+}
+
+func loadKey(config KeyConfig, tokenLifetime time.Duration) (*Key, error) {
+	if config.Kid == "" {
+		return nil, errors.New("kid must not empty")
+	}
+
+	key := &Key{
+		Kid:    config.Kid,
+		Method: jwt.GetSigningMethod(config.Algorithm),
+	}
+
+	switch config.Algorithm {
+	case AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA:
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", config.Algorithm)
+	}
+
+	if config.RetiredAt != "" {
+		retiredAt, err := time.Parse(time.RFC3339, config.RetiredAt)
+		if err != nil {
+			return nil, err
+		}
+		key.ValidUntil = retiredAt.Add(tokenLifetime)
+	}
+
+	if config.PrivateKeyFile != "" {
+		privateKey, err := parsePrivateKey(config.Algorithm, config.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key.PrivateKey = privateKey
+		key.PublicKey = privateKey.(interface{ Public() crypto.PublicKey }).Public()
+	}
+
+	if config.PublicKeyFile != "" {
+		publicKey, err := parsePublicKey(config.Algorithm, config.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key.PublicKey = publicKey
+	}
+
+	if key.PublicKey == nil {
+		return nil, errors.New("private_key_file or public_key_file must be filled")
+	}
+
+	return key, nil
+}
+
+func parsePrivateKey(algorithm, filename string) (crypto.PrivateKey, error) {
+	pem, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.ParseRSAPrivateKeyFromPEM(pem)
+	case AlgorithmES256:
+		return jwt.ParseECPrivateKeyFromPEM(pem)
+	default:
+		return jwt.ParseEdPrivateKeyFromPEM(pem)
+	}
+}
+
+func parsePublicKey(algorithm, filename string) (crypto.PublicKey, error) {
+	pem, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.ParseRSAPublicKeyFromPEM(pem)
+	case AlgorithmES256:
+		return jwt.ParseECPublicKeyFromPEM(pem)
+	default:
+		return jwt.ParseEdPublicKeyFromPEM(pem)
+	}
+}
+
+// SigningKey return the key used to sign new token
+func (k *KeySet) SigningKey() *Key {
+	return k.keys[k.signingKid]
+}
+
+// VerificationKey return the trusted key for the kid header
+func (k *KeySet) VerificationKey(kid string) (*Key, error) {
+	key, exist := k.keys[kid]
+	if !exist || key.isExpired() {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// PublicKeys return every asymmetric key still trusted, sorted by kid
+func (k *KeySet) PublicKeys() []*Key {
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		if key.Kid == "" || key.isExpired() {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Kid < keys[j].Kid
+	})
+	return keys
+}
+
+func (k *Key) isExpired() bool {
+	return !k.ValidUntil.IsZero() && time.Now().After(k.ValidUntil)
+}
+
+func isSupportedPublicKey(publicKey crypto.PublicKey) bool {
+	switch publicKey.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return true
+	}
+	return false
+}
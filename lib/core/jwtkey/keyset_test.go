@@ -0,0 +1,225 @@
+package jwtkey
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeKeyFiles write the PKCS8 private key and the PKIX public key in the test directory
+func writeKeyFiles(t *testing.T, name string, privateKey crypto.Signer) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateFile := filepath.Join(dir, name+".pem")
+	publicFile := filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privateFile, publicFile
+}
+
+func TestLoadKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaPrivate, rsaPublic := writeKeyFiles(t, "rsa", rsaKey)
+	ecPrivate, _ := writeKeyFiles(t, "ec", ecKey)
+	edPrivate, edPublic := writeKeyFiles(t, "ed", edKey)
+
+	recentlyRetired := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	longRetired := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		config     Config
+		wantErr    bool
+		wantKid    string
+		wantAlg    string
+		wantPublic []string
+	}{
+		{
+			name:    "legacy secret",
+			config:  Config{Secret: "secret"},
+			wantKid: "",
+			wantAlg: AlgorithmHS256,
+		},
+		{
+			name: "rsa signing key",
+			config: Config{SigningKid: "rsa-1", Keys: []KeyConfig{
+				{Kid: "rsa-1", Algorithm: AlgorithmRS256, PrivateKeyFile: rsaPrivate},
+			}},
+			wantKid:    "rsa-1",
+			wantAlg:    AlgorithmRS256,
+			wantPublic: []string{"rsa-1"},
+		},
+		{
+			name: "ec signing key",
+			config: Config{SigningKid: "ec-1", Keys: []KeyConfig{
+				{Kid: "ec-1", Algorithm: AlgorithmES256, PrivateKeyFile: ecPrivate},
+			}},
+			wantKid:    "ec-1",
+			wantAlg:    AlgorithmES256,
+			wantPublic: []string{"ec-1"},
+		},
+		{
+			name: "signing kid chosen among several keys",
+			config: Config{SigningKid: "ed-2", TokenLifetime: time.Hour, Keys: []KeyConfig{
+				{Kid: "rsa-1", Algorithm: AlgorithmRS256, PublicKeyFile: rsaPublic, RetiredAt: recentlyRetired},
+				{Kid: "ed-2", Algorithm: AlgorithmEdDSA, PrivateKeyFile: edPrivate},
+			}},
+			wantKid:    "ed-2",
+			wantAlg:    AlgorithmEdDSA,
+			wantPublic: []string{"ed-2", "rsa-1"},
+		},
+		{
+			name: "retired key dropped after the token lifetime",
+			config: Config{SigningKid: "ed-2", TokenLifetime: time.Hour, Keys: []KeyConfig{
+				{Kid: "rsa-1", Algorithm: AlgorithmRS256, PublicKeyFile: rsaPublic, RetiredAt: longRetired},
+				{Kid: "ed-2", Algorithm: AlgorithmEdDSA, PrivateKeyFile: edPrivate},
+			}},
+			wantKid:    "ed-2",
+			wantAlg:    AlgorithmEdDSA,
+			wantPublic: []string{"ed-2"},
+		},
+		{
+			name: "signing kid missing",
+			config: Config{SigningKid: "other", Keys: []KeyConfig{
+				{Kid: "rsa-1", Algorithm: AlgorithmRS256, PrivateKeyFile: rsaPrivate},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "signing key without private key",
+			config: Config{SigningKid: "ed-1", Keys: []KeyConfig{
+				{Kid: "ed-1", Algorithm: AlgorithmEdDSA, PublicKeyFile: edPublic},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "signing key retired",
+			config: Config{SigningKid: "rsa-1", Keys: []KeyConfig{
+				{Kid: "rsa-1", Algorithm: AlgorithmRS256, PrivateKeyFile: rsaPrivate, RetiredAt: recentlyRetired},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unsupported algorithm",
+			config: Config{SigningKid: "hs-1", Keys: []KeyConfig{
+				{Kid: "hs-1", Algorithm: AlgorithmHS256, PrivateKeyFile: rsaPrivate},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "algorithm does not match the key",
+			config: Config{SigningKid: "ec-1", Keys: []KeyConfig{
+				{Kid: "ec-1", Algorithm: AlgorithmES256, PrivateKeyFile: rsaPrivate},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "empty kid",
+			config: Config{Keys: []KeyConfig{
+				{Algorithm: AlgorithmRS256, PrivateKeyFile: rsaPrivate},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keySet, err := LoadKeySet(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadKeySet() must return an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadKeySet() error : %v", err)
+			}
+
+			signingKey := keySet.SigningKey()
+			if signingKey.Kid != tt.wantKid || signingKey.Method.Alg() != tt.wantAlg {
+				t.Errorf("SigningKey() = (%s, %s), want (%s, %s)", signingKey.Kid, signingKey.Method.Alg(), tt.wantKid, tt.wantAlg)
+			}
+
+			verificationKey, err := keySet.VerificationKey(tt.wantKid)
+			if err != nil || verificationKey != signingKey {
+				t.Errorf("VerificationKey(%q) = (%v, %v), want the signing key", tt.wantKid, verificationKey, err)
+			}
+			if _, err := keySet.VerificationKey("unknown"); !errors.Is(err, ErrKeyNotFound) {
+				t.Errorf("VerificationKey(unknown) error = %v, want ErrKeyNotFound", err)
+			}
+
+			publicKids := []string{}
+			for _, key := range keySet.PublicKeys() {
+				publicKids = append(publicKids, key.Kid)
+			}
+			if len(publicKids) != len(tt.wantPublic) {
+				t.Fatalf("PublicKeys() = %v, want %v", publicKids, tt.wantPublic)
+			}
+			for i := range publicKids {
+				if publicKids[i] != tt.wantPublic[i] {
+					t.Errorf("PublicKeys() = %v, want %v", publicKids, tt.wantPublic)
+				}
+			}
+			if jwks := keySet.JWKS(); len(jwks.Keys) != len(tt.wantPublic) {
+				t.Errorf("JWKS() has %d keys, want %d", len(jwks.Keys), len(tt.wantPublic))
+			}
+		})
+	}
+}
+
+func TestVerificationKeyRetired(t *testing.T) {
+	keySet := &KeySet{keys: map[string]*Key{
+		"retired": {Kid: "retired", ValidUntil: time.Now().Add(-time.Second)},
+		"grace":   {Kid: "grace", ValidUntil: time.Now().Add(time.Hour)},
+	}}
+
+	tests := []struct {
+		kid     string
+		wantErr error
+	}{
+		{"retired", ErrKeyNotFound},
+		{"grace", nil},
+		{"", ErrKeyNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := keySet.VerificationKey(tt.kid); !errors.Is(err, tt.wantErr) {
+			t.Errorf("VerificationKey(%q) error = %v, want %v", tt.kid, err, tt.wantErr)
+		}
+	}
+}
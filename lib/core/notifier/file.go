@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+type fileMessage struct {
+	Message
+	SentAt time.Time `json:"sent_at"`
+}
+
+type fileNotifier struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+func NewFileNotifier(filePath string) (Notifier, error) {
+	if filePath == "" {
+		return nil, errors.New("notifier file path must not empty")
+	}
+	return &fileNotifier{filePath: filePath}, nil
+}
+
+func (n *fileNotifier) Send(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(fileMessage{Message: msg, SentAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,17 @@
+package notifier
+
+import (
+	"context"
+	"log"
+)
+
+type logNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Send(ctx context.Context, msg Message) error {
+	log.Printf("notifier >>> channel: %s, to: %s, subject: %s, body: %s", msg.Channel, msg.To, msg.Subject, msg.Body)
+	return nil
+}
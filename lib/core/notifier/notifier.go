@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+)
+
+// Sinks
+const (
+	SinkLog  = "log"
+	SinkFile = "file"
+)
+
+// Message is a single email or sms, To is the address or the phone number of the channel
+type Message struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notifier deliver the message to the member.
+// The log and file sinks only write the message, they are meant for local development and testing
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type Config struct {
+	// Sink is log or file.
+	// Optional. Default value log.
+	Sink string
+
+	// FilePath is the file the messages are appended to, one json per line.
+	// Required for the file sink.
+	FilePath string
+}
+
+func NewNotifier(config Config) (Notifier, error) {
+	switch config.Sink {
+	case "", SinkLog:
+		return NewLogNotifier(), nil
+	case SinkFile:
+		return NewFileNotifier(config.FilePath)
+	}
+	return nil, fmt.Errorf("unsupported notifier sink %s", config.Sink)
+}
@@ -0,0 +1,180 @@
+// Package oidcclient is the relying party side of OpenID Connect,
+// the authorization code flow with PKCE and the id token validation against the provider JWKS
+package oidcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrProviderNotFound = errors.New("oidc provider is not configured")
+	ErrIdTokenMissing   = errors.New("token response does not contain an id_token")
+	ErrNonceMismatch    = errors.New("id token nonce does not match")
+)
+
+type (
+	Config struct {
+		// RedirectUrl is the callback registered at every provider, {provider} is replaced by the provider name
+		RedirectUrl string `mapstructure:"redirect_url"`
+
+		Providers []ProviderConfig `mapstructure:"providers"`
+	}
+
+	ProviderConfig struct {
+		// Name is used in the url, e.g. google, microsoft or keycloak
+		Name string `mapstructure:"name"`
+
+		// Issuer is the discovery url without /.well-known/openid-configuration
+		Issuer string `mapstructure:"issuer"`
+
+		ClientId     string `mapstructure:"client_id"`
+		ClientSecret string `mapstructure:"client_secret"`
+
+		// Scopes are added to openid.
+		// Optional. Default value email and profile.
+		Scopes []string `mapstructure:"scopes"`
+	}
+)
+
+// Identity is read from the validated id token
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type provider struct {
+	config   ProviderConfig
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// Client discover a provider on its first use, so the service start even when a provider is down
+type Client struct {
+	config    Config
+	mu        sync.Mutex
+	providers map[string]*provider
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		config:    config,
+		providers: map[string]*provider{},
+	}
+}
+
+// AuthCodeURL return the url the member is sent to, with the S256 code challenge of the verifier
+func (c *Client) AuthCodeURL(ctx context.Context, providerName, state, nonce, codeVerifier string) (string, error) {
+	p, err := c.provider(ctx, providerName)
+	if err != nil {
+		return "", err
+	}
+
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+// Exchange redeem the code and validate the id token signature, issuer, audience, expiry and nonce
+func (c *Client) Exchange(ctx context.Context, providerName, code, codeVerifier, nonce string) (*Identity, error) {
+	p, err := c.provider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIdToken == "" {
+		return nil, ErrIdTokenMissing
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email         string      `json:"email"`
+		EmailVerified interface{} `json:"email_verified"`
+		Name          string      `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		Provider:      p.config.Name,
+		Subject:       idToken.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: isTrue(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+func (c *Client) provider(ctx context.Context, name string) (*provider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, exist := c.providers[name]; exist {
+		return p, nil
+	}
+
+	for _, config := range c.config.Providers {
+		if config.Name != name {
+			continue
+		}
+
+		// the discovery must outlive the request which trigger it
+		oidcProvider, err := oidc.NewProvider(context.WithoutCancel(ctx), config.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("discover oidc provider %s : %w", name, err)
+		}
+
+		scopes := config.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"email", "profile"}
+		}
+
+		p := &provider{
+			config: config,
+			oauth2: oauth2.Config{
+				ClientID:     config.ClientId,
+				ClientSecret: config.ClientSecret,
+				Endpoint:     oidcProvider.Endpoint(),
+				RedirectURL:  strings.ReplaceAll(c.config.RedirectUrl, "{provider}", name),
+				Scopes:       append([]string{oidc.ScopeOpenID}, scopes...),
+			},
+			verifier: oidcProvider.Verifier(&oidc.Config{ClientID: config.ClientId}),
+		}
+		c.providers[name] = p
+
+		return p, nil
+	}
+
+	return nil, ErrProviderNotFound
+}
+
+// isTrue accept email_verified as a boolean or, as some providers send it, a string
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+type (
+	// Argon2idConfig defines the parameters of argon2id hashing.
+	Argon2idConfig struct {
+		// Memory in KiB used by the algorithm.
+		// Optional. Default value 65536 (64 MiB).
+		MemoryKiB uint32
+
+		// Iterations is the number of passes over the memory.
+		// Optional. Default value 3.
+		Iterations uint32
+
+		// Parallelism is the number of threads used.
+		// Optional. Default value 2.
+		Parallelism uint8
+
+		// SaltLength in bytes.
+		// Optional. Default value 16.
+		SaltLength uint32
+
+		// KeyLength in bytes.
+		// Optional. Default value 32.
+		KeyLength uint32
+	}
+)
+
+var (
+	// DefaultArgon2idConfig is the default argon2id config.
+	DefaultArgon2idConfig = Argon2idConfig{
+		MemoryKiB:   64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+)
+
+type argon2idHasher struct {
+	config Argon2idConfig
+}
+
+func NewArgon2idHasher(config Argon2idConfig) Hasher {
+	if config.MemoryKiB == 0 {
+		config.MemoryKiB = DefaultArgon2idConfig.MemoryKiB
+	}
+	if config.Iterations == 0 {
+		config.Iterations = DefaultArgon2idConfig.Iterations
+	}
+	if config.Parallelism == 0 {
+		config.Parallelism = DefaultArgon2idConfig.Parallelism
+	}
+	if config.SaltLength == 0 {
+		config.SaltLength = DefaultArgon2idConfig.SaltLength
+	}
+	if config.KeyLength == 0 {
+		config.KeyLength = DefaultArgon2idConfig.KeyLength
+	}
+	return &argon2idHasher{config: config}
+}
+
+// Hash encode the password in PHC string format
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func (h *argon2idHasher) Hash(plain string) (string, error) {
+	salt := make([]byte, h.config.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(plain), salt, h.config.Iterations, h.config.MemoryKiB, h.config.Parallelism, h.config.KeyLength)
+
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		h.config.MemoryKiB,
+		h.config.Iterations,
+		h.config.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *argon2idHasher) Verify(plain, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(plain), salt, params.Iterations, params.MemoryKiB, params.Parallelism, uint32(len(key)))
+
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+func (h *argon2idHasher) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return params.MemoryKiB != h.config.MemoryKiB ||
+		params.Iterations != h.config.Iterations ||
+		params.Parallelism != h.config.Parallelism ||
+		uint32(len(salt)) != h.config.SaltLength ||
+		uint32(len(key)) != h.config.KeyLength
+}
+
+func decodeArgon2id(encoded string) (Argon2idConfig, []byte, []byte, error) {
+	var params Argon2idConfig
+
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", "<salt>", "<key>"
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("incompatible argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.MemoryKiB, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,68 @@
+package password
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type (
+	// BcryptConfig defines the parameters of bcrypt hashing.
+	BcryptConfig struct {
+		// Cost of the hashing, between 4 and 31.
+		// Optional. Default value 12.
+		Cost int
+	}
+)
+
+var (
+	// DefaultBcryptConfig is the default bcrypt config.
+	DefaultBcryptConfig = BcryptConfig{
+		Cost: 12,
+	}
+)
+
+type bcryptHasher struct {
+	config BcryptConfig
+}
+
+func NewBcryptHasher(config BcryptConfig) Hasher {
+	if config.Cost < bcrypt.MinCost || config.Cost > bcrypt.MaxCost {
+		config.Cost = DefaultBcryptConfig.Cost
+	}
+	return &bcryptHasher{config: config}
+}
+
+func (h *bcryptHasher) Hash(plain string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), h.config.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (h *bcryptHasher) Verify(plain, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(plain))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *bcryptHasher) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+	return cost != h.config.Cost
+}
@@ -0,0 +1,36 @@
+package password
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"regexp"
+)
+
+var md5HexPattern = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
+// md5Hasher only verify the legacy unsalted md5 hex digest.
+// It must never be used to hash new password
+type md5Hasher struct{}
+
+func NewMD5Hasher() Hasher {
+	return &md5Hasher{}
+}
+
+func (h *md5Hasher) Hash(plain string) (string, error) {
+	return "", errors.New("md5 is only supported to verify legacy password")
+}
+
+func (h *md5Hasher) Verify(plain, encoded string) (bool, error) {
+	sum := md5.Sum([]byte(plain))
+	return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(encoded)) == 1, nil
+}
+
+func (h *md5Hasher) Identify(encoded string) bool {
+	return md5HexPattern.MatchString(encoded)
+}
+
+func (h *md5Hasher) NeedsRehash(encoded string) bool {
+	return true
+}
@@ -0,0 +1,124 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Algorithms
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmMD5      = "md5"
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// Hasher hash and verify a single password algorithm
+type Hasher interface {
+	// Hash return the encoded hash of the plain password
+	Hash(plain string) (string, error)
+
+	// Verify compare the plain password against the encoded hash
+	Verify(plain, encoded string) (bool, error)
+
+	// Identify return true when the encoded hash is produced by this hasher
+	Identify(encoded string) bool
+
+	// NeedsRehash return true when the encoded hash was produced with other parameters
+	NeedsRehash(encoded string) bool
+}
+
+type (
+	// Config defines the config for password hashing.
+	Config struct {
+		// Algorithm used to hash new password.
+		// Optional. Default value "argon2id".
+		// Possible values: "argon2id", "bcrypt"
+		Algorithm string
+
+		Argon2id Argon2idConfig
+		Bcrypt   BcryptConfig
+	}
+)
+
+var (
+	// DefaultConfig is the default password hashing config.
+	DefaultConfig = Config{
+		Algorithm: AlgorithmArgon2id,
+		Argon2id:  DefaultArgon2idConfig,
+		Bcrypt:    DefaultBcryptConfig,
+	}
+)
+
+// Manager hash new password with the current algorithm and verify every known algorithm,
+// so legacy hashes keep working until they are upgraded
+type Manager struct {
+	current Hasher
+	hashers []Hasher
+	// dummyHash is verified when there is no account, so an unknown account take as long as a wrong password
+	dummyHash string
+}
+
+func NewManager(config Config) (*Manager, error) {
+	argon2idHasher := NewArgon2idHasher(config.Argon2id)
+	bcryptHasher := NewBcryptHasher(config.Bcrypt)
+
+	var current Hasher
+	switch config.Algorithm {
+	case "", AlgorithmArgon2id:
+		current = argon2idHasher
+	case AlgorithmBcrypt:
+		current = bcryptHasher
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %s", config.Algorithm)
+	}
+
+	dummyHash, err := current.Hash("dummy password of an unknown account")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		current:   current,
+		hashers:   []Hasher{argon2idHasher, bcryptHasher, NewMD5Hasher()},
+		dummyHash: dummyHash,
+	}, nil
+}
+
+// Hash return the encoded hash of the plain password using the current algorithm
+func (m *Manager) Hash(plain string) (string, error) {
+	return m.current.Hash(plain)
+}
+
+// Verify compare the plain password against an encoded hash of any known algorithm
+func (m *Manager) Verify(plain, encoded string) (bool, error) {
+	hasher := m.find(encoded)
+	if hasher == nil {
+		return false, ErrUnknownHashFormat
+	}
+	return hasher.Verify(plain, encoded)
+}
+
+// DummyHash return a hash of the current algorithm which no password match,
+// verify it when the account does not exist to not reveal it by the response time
+func (m *Manager) DummyHash() string {
+	return m.dummyHash
+}
+
+// NeedsRehash return true when the encoded hash is not produced by the current algorithm and parameters
+func (m *Manager) NeedsRehash(encoded string) bool {
+	if !m.current.Identify(encoded) {
+		return true
+	}
+	return m.current.NeedsRehash(encoded)
+}
+
+func (m *Manager) find(encoded string) Hasher {
+	for _, hasher := range m.hashers {
+		if hasher.Identify(encoded) {
+			return hasher
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package password
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// fastArgon2idConfig keep the tests quick, the parameters are checked by NeedsRehash
+var fastArgon2idConfig = Argon2idConfig{MemoryKiB: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+var fastBcryptConfig = BcryptConfig{Cost: 4}
+
+func md5Hex(plain string) string {
+	sum := md5.Sum([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHasherHashAndVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher Hasher
+		prefix string
+	}{
+		{"argon2id", NewArgon2idHasher(fastArgon2idConfig), "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{"bcrypt", NewBcryptHasher(fastBcryptConfig), "$2a$04$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash() error : %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Errorf("Hash() = %s, want prefix %s", encoded, tt.prefix)
+			}
+			if !tt.hasher.Identify(encoded) {
+				t.Error("Identify() must recognize its own hash")
+			}
+
+			other, err := tt.hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash() error : %v", err)
+			}
+			if other == encoded {
+				t.Error("Hash() must use a random salt")
+			}
+
+			ok, err := tt.hasher.Verify("correct horse", encoded)
+			if err != nil || !ok {
+				t.Errorf("Verify(correct) = (%v, %v), want (true, nil)", ok, err)
+			}
+			ok, err = tt.hasher.Verify("wrong horse", encoded)
+			if err != nil || ok {
+				t.Errorf("Verify(wrong) = (%v, %v), want (false, nil)", ok, err)
+			}
+			if tt.hasher.NeedsRehash(encoded) {
+				t.Error("NeedsRehash() must be false for a hash of the same parameters")
+			}
+		})
+	}
+}
+
+func TestHasherNeedsRehash(t *testing.T) {
+	argon2idHash, err := NewArgon2idHasher(fastArgon2idConfig).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := NewBcryptHasher(fastBcryptConfig).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stronger := fastArgon2idConfig
+	stronger.Iterations = 2
+
+	tests := []struct {
+		name    string
+		hasher  Hasher
+		encoded string
+		want    bool
+	}{
+		{"argon2id same parameters", NewArgon2idHasher(fastArgon2idConfig), argon2idHash, false},
+		{"argon2id more iterations", NewArgon2idHasher(stronger), argon2idHash, true},
+		{"argon2id malformed", NewArgon2idHasher(fastArgon2idConfig), "$argon2id$v=19$broken", true},
+		{"bcrypt same cost", NewBcryptHasher(fastBcryptConfig), bcryptHash, false},
+		{"bcrypt higher cost", NewBcryptHasher(BcryptConfig{Cost: 5}), bcryptHash, true},
+		{"md5 always", NewMD5Hasher(), md5Hex("secret"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.encoded); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMD5Hasher(t *testing.T) {
+	hasher := NewMD5Hasher()
+	legacy := md5Hex("legacy password")
+
+	if _, err := hasher.Hash("legacy password"); err == nil {
+		t.Error("Hash() must refuse to produce a md5 hash")
+	}
+
+	tests := []struct {
+		name     string
+		encoded  string
+		identify bool
+	}{
+		{"lowercase hex", legacy, true},
+		{"uppercase hex", strings.ToUpper(legacy), false},
+		{"too short", legacy[:31], false},
+		{"bcrypt", "$2a$04$abcdefghijklmnopqrstuv", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasher.Identify(tt.encoded); got != tt.identify {
+				t.Errorf("Identify() = %v, want %v", got, tt.identify)
+			}
+		})
+	}
+
+	if ok, _ := hasher.Verify("legacy password", legacy); !ok {
+		t.Error("Verify() must match the legacy digest")
+	}
+	if ok, _ := hasher.Verify("other password", legacy); ok {
+		t.Error("Verify() must not match another password")
+	}
+}
+
+func TestManager(t *testing.T) {
+	manager, err := NewManager(Config{Algorithm: AlgorithmArgon2id, Argon2id: fastArgon2idConfig, Bcrypt: fastBcryptConfig})
+	if err != nil {
+		t.Fatalf("NewManager() error : %v", err)
+	}
+
+	current, err := manager.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := NewBcryptHasher(fastBcryptConfig).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		plain       string
+		encoded     string
+		wantOk      bool
+		wantErr     error
+		needsRehash bool
+	}{
+		{"current algorithm", "secret", current, true, nil, false},
+		{"legacy bcrypt", "secret", bcryptHash, true, nil, true},
+		{"legacy md5", "secret", md5Hex("secret"), true, nil, true},
+		{"legacy md5 wrong password", "other", md5Hex("secret"), false, nil, true},
+		{"dummy hash", "dummy password", manager.DummyHash(), false, nil, false},
+		{"unknown format", "secret", "plaintext", false, ErrUnknownHashFormat, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := manager.Verify(tt.plain, tt.encoded)
+			if ok != tt.wantOk || err != tt.wantErr {
+				t.Errorf("Verify() = (%v, %v), want (%v, %v)", ok, err, tt.wantOk, tt.wantErr)
+			}
+			if got := manager.NeedsRehash(tt.encoded); got != tt.needsRehash {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.needsRehash)
+			}
+		})
+	}
+}
+
+func TestNewManagerUnsupportedAlgorithm(t *testing.T) {
+	if _, err := NewManager(Config{Algorithm: AlgorithmMD5}); err == nil {
+		t.Error("NewManager() must refuse md5 as the current algorithm")
+	}
+}
@@ -0,0 +1,154 @@
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Rules of a policy, used as the key of a Violation
+const (
+	RuleMinLength = "min_length"
+	RuleUpper     = "upper"
+	RuleLower     = "lower"
+	RuleDigit     = "digit"
+	RuleSymbol    = "symbol"
+	RuleBreached  = "breached"
+	RuleHistory   = "history"
+)
+
+type (
+	// PolicyConfig defines the rules a new password must follow.
+	PolicyConfig struct {
+		// MinLength is counted in characters, not bytes.
+		// Optional. Default value 8.
+		MinLength int `mapstructure:"min_length"`
+
+		RequireUpper  bool `mapstructure:"require_upper"`
+		RequireLower  bool `mapstructure:"require_lower"`
+		RequireDigit  bool `mapstructure:"require_digit"`
+		RequireSymbol bool `mapstructure:"require_symbol"`
+
+		// RejectBreached reject the password found in the breached list.
+		RejectBreached bool `mapstructure:"reject_breached"`
+
+		// HistorySize is the number of previous passwords which can not be used again.
+		// Optional. Default value 0, the history is not checked.
+		HistorySize int `mapstructure:"history_size"`
+	}
+)
+
+var (
+	// DefaultPolicyConfig is the default password policy.
+	DefaultPolicyConfig = PolicyConfig{
+		MinLength: 8,
+	}
+)
+
+// Violation is a rule the password does not follow
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// BreachedList is a set of known breached or common passwords, compared case insensitively
+type BreachedList map[string]struct{}
+
+// LoadBreachedList read one password per line, an empty path return an empty list
+func LoadBreachedList(path string) (BreachedList, error) {
+	list := BreachedList{}
+	if path == "" {
+		return list, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		list[strings.ToLower(line)] = struct{}{}
+	}
+
+	return list, scanner.Err()
+}
+
+func (l BreachedList) Contains(plain string) bool {
+	_, exist := l[strings.ToLower(plain)]
+	return exist
+}
+
+type Policy struct {
+	config   PolicyConfig
+	breached BreachedList
+}
+
+func NewPolicy(config PolicyConfig, breached BreachedList) Policy {
+	if config.MinLength == 0 {
+		config.MinLength = DefaultPolicyConfig.MinLength
+	}
+
+	return Policy{
+		config:   config,
+		breached: breached,
+	}
+}
+
+func (p Policy) HistorySize() int {
+	return p.config.HistorySize
+}
+
+// Check return every rule the plain password does not follow, the history is checked by the caller
+// since it need the stored hashes
+func (p Policy) Check(plain string) []Violation {
+	violations := []Violation{}
+
+	if len([]rune(plain)) < p.config.MinLength {
+		violations = append(violations, Violation{RuleMinLength, fmt.Sprintf("must be at least %d characters", p.config.MinLength)})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, c := range plain {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c) || unicode.IsSpace(c):
+			hasSymbol = true
+		}
+	}
+
+	if p.config.RequireUpper && !hasUpper {
+		violations = append(violations, Violation{RuleUpper, "must contain an uppercase letter"})
+	}
+	if p.config.RequireLower && !hasLower {
+		violations = append(violations, Violation{RuleLower, "must contain a lowercase letter"})
+	}
+	if p.config.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{RuleDigit, "must contain a digit"})
+	}
+	if p.config.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{RuleSymbol, "must contain a symbol"})
+	}
+	if p.config.RejectBreached && p.breached.Contains(plain) {
+		violations = append(violations, Violation{RuleBreached, "is too common or has appeared in a data breach"})
+	}
+
+	return violations
+}
+
+// HistoryViolation is returned when the password is one of the last HistorySize passwords
+func (p Policy) HistoryViolation() Violation {
+	return Violation{RuleHistory, fmt.Sprintf("must not be one of the last %d passwords", p.config.HistorySize)}
+}
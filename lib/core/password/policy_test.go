@@ -0,0 +1,103 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func violatedRules(violations []Violation) []string {
+	rules := []string{}
+	for _, violation := range violations {
+		rules = append(rules, violation.Rule)
+	}
+	return rules
+}
+
+func TestPolicyCheck(t *testing.T) {
+	strict := PolicyConfig{
+		MinLength:      10,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSymbol:  true,
+		RejectBreached: true,
+	}
+	breached := BreachedList{"password123!a": {}}
+
+	tests := []struct {
+		name   string
+		config PolicyConfig
+		plain  string
+		want   []string
+	}{
+		{"default min length too short", PolicyConfig{}, "abcdefg", []string{RuleMinLength}},
+		{"default min length reached", PolicyConfig{}, "abcdefgh", []string{}},
+		{"min length counted in characters", PolicyConfig{MinLength: 4}, "äöüß", []string{}},
+		{"strict valid", strict, "Correct-Horse-1", []string{}},
+		{"strict too short", strict, "Co-Horse1", []string{RuleMinLength}},
+		{"strict missing upper", strict, "correct-horse-1", []string{RuleUpper}},
+		{"strict missing lower", strict, "CORRECT-HORSE-1", []string{RuleLower}},
+		{"strict missing digit", strict, "Correct-Horse-X", []string{RuleDigit}},
+		{"strict missing symbol", strict, "CorrectHorse1", []string{RuleSymbol}},
+		{"space is a symbol", strict, "Correct Horse 1", []string{}},
+		{"strict breached case insensitive", strict, "PASSWORD123!a", []string{RuleBreached}},
+		{"breached not rejected", PolicyConfig{}, "password123!a", []string{}},
+		{"every rule", strict, "", []string{RuleMinLength, RuleUpper, RuleLower, RuleDigit, RuleSymbol}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violatedRules(NewPolicy(tt.config, breached).Check(tt.plain))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check(%q) = %v, want %v", tt.plain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyHistoryViolation(t *testing.T) {
+	policy := NewPolicy(PolicyConfig{HistorySize: 3}, nil)
+	if policy.HistorySize() != 3 {
+		t.Errorf("HistorySize() = %d, want 3", policy.HistorySize())
+	}
+
+	violation := policy.HistoryViolation()
+	if violation.Rule != RuleHistory || violation.Message != "must not be one of the last 3 passwords" {
+		t.Errorf("HistoryViolation() = %+v", violation)
+	}
+}
+
+func TestLoadBreachedList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte("# comment\n\n  Qwerty  \nletmein\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := LoadBreachedList(path)
+	if err != nil {
+		t.Fatalf("LoadBreachedList() error : %v", err)
+	}
+
+	tests := []struct {
+		plain string
+		want  bool
+	}{
+		{"qwerty", true},
+		{"QWERTY", true},
+		{"letmein", true},
+		{"# comment", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := list.Contains(tt.plain); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.plain, got, tt.want)
+		}
+	}
+
+	empty, err := LoadBreachedList("")
+	if err != nil || len(empty) != 0 {
+		t.Errorf("LoadBreachedList(\"\") = (%v, %v), want an empty list", empty, err)
+	}
+}
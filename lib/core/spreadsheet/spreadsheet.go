@@ -0,0 +1,162 @@
+// Package spreadsheet read the rows of a csv file or of the first sheet of an xlsx workbook as strings,
+// the xlsx file is read with the standard library only
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats
+const (
+	FormatCsv  = "csv"
+	FormatXlsx = "xlsx"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("spreadsheet format must be csv or xlsx")
+	ErrTooManyRows       = errors.New("spreadsheet has too many rows")
+	ErrTooManyColumns    = errors.New("spreadsheet has too many columns")
+)
+
+// Limit stop the reading of a file larger than expected, zero means no limit.
+// The first non blank row is the header, the cells of the next rows beyond it are dropped
+type Limit struct {
+	// MaxRows is the last row number read, the header and the blank rows are counted
+	MaxRows int
+	// MaxColumns is the width of the header, a non empty cell beyond it is refused
+	MaxColumns int
+}
+
+// zipSignature start every xlsx file
+var zipSignature = []byte("PK\x03\x04")
+
+// DetectFormat use the file name extension, the content is looked at when the extension is unknown
+func DetectFormat(fileName string, data []byte) string {
+	lowerName := strings.ToLower(fileName)
+	switch {
+	case strings.HasSuffix(lowerName, "."+FormatXlsx):
+		return FormatXlsx
+	case strings.HasSuffix(lowerName, "."+FormatCsv):
+		return FormatCsv
+	case bytes.HasPrefix(data, zipSignature):
+		return FormatXlsx
+	}
+	return FormatCsv
+}
+
+// Read return the rows with the empty trailing cells removed, a blank row is kept empty
+// so the index plus one is the line of the csv or the row number of the sheet
+func Read(format string, data []byte, limit Limit) ([][]string, error) {
+	switch format {
+	case FormatCsv:
+		return ReadCsv(data, limit)
+	case FormatXlsx:
+		return ReadXlsx(data, limit)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ReadCsv accept a comma or semicolon separated file, the separator is guessed from the first line
+func ReadCsv(data []byte, limit Limit) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	firstLine := data
+	if end := bytes.IndexByte(data, '\n'); end >= 0 {
+		firstLine = data[:end]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	limiter := rowLimiter{limit: limit}
+	rows := [][]string{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv : %w", err)
+		}
+
+		// the csv reader skip the blank lines, they are added back
+		line, _ := reader.FieldPos(0)
+		if err := limiter.checkRow(line); err != nil {
+			return nil, err
+		}
+		for len(rows) < line-1 {
+			rows = append(rows, []string{})
+		}
+
+		cells := []string{}
+		for column, value := range record {
+			value = strings.TrimSpace(value)
+			keep, err := limiter.keepCell(column, value)
+			if err != nil {
+				return nil, err
+			}
+			if keep {
+				cells = append(cells, value)
+			}
+		}
+		row := trimRow(cells)
+		limiter.endRow(row)
+		rows = append(rows, row)
+	}
+}
+
+// rowLimiter apply a Limit to the rows in the order they are read
+type rowLimiter struct {
+	limit Limit
+	// width is the length of the header once it is read
+	width int
+}
+
+// checkRow refuse a one based row number beyond MaxRows, before the row is read
+func (r *rowLimiter) checkRow(rowNumber int) error {
+	if r.limit.MaxRows > 0 && rowNumber > r.limit.MaxRows {
+		return ErrTooManyRows
+	}
+	return nil
+}
+
+// keepCell tell whether the cell of the zero based column is kept, the value is trimmed
+func (r *rowLimiter) keepCell(column int, value string) (bool, error) {
+	if r.width > 0 {
+		return column < r.width, nil
+	}
+	if r.limit.MaxColumns > 0 && column >= r.limit.MaxColumns {
+		if value != "" {
+			return false, ErrTooManyColumns
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// endRow take the first non blank row as the header
+func (r *rowLimiter) endRow(row []string) {
+	if r.width == 0 && len(row) > 0 {
+		r.width = len(row)
+	}
+}
+
+func trimRow(row []string) []string {
+	for i := range row {
+		row[i] = strings.TrimSpace(row[i])
+	}
+	for len(row) > 0 && row[len(row)-1] == "" {
+		row = row[:len(row)-1]
+	}
+	return row
+}
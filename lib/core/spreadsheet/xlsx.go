@@ -0,0 +1,277 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXlsxPartSize limit the uncompressed size of a part of the workbook, a small zip can expand a lot
+const maxXlsxPartSize = 64 << 20
+
+// maxXlsxRow and maxXlsxColumn are the size of a sheet, XFD is the last column
+const (
+	maxXlsxRow    = 1048576
+	maxXlsxColumn = 16384
+)
+
+var ErrSheetNotFound = errors.New("xlsx file does not contain a sheet")
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelationId string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a plain or a rich text, the rich text is made of runs
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r xlsxText) String() string {
+	if len(r.Runs) == 0 {
+		return r.Text
+	}
+	var text strings.Builder
+	for _, run := range r.Runs {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxCell struct {
+	Ref       string   `xml:"r,attr"`
+	Type      string   `xml:"t,attr"`
+	Value     string   `xml:"v"`
+	InlineStr xlsxText `xml:"is"`
+}
+
+// ReadXlsx read the first sheet of the workbook, a number is returned as it is stored
+// and a date is its serial number
+func ReadXlsx(data []byte, limit Limit) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read xlsx : %w", err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sharedStrings := xlsxSharedStrings{}
+	if file, exist := files["xl/sharedStrings.xml"]; exist {
+		if err := decodeXlsxPart(file, &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, exist := files[firstSheetPath(files)]
+	if !exist {
+		return nil, ErrSheetNotFound
+	}
+	return readXlsxSheet(sheetFile, sharedStrings, limit)
+}
+
+// readXlsxSheet decode the sheet one cell at a time, it stop at the first row beyond the limit
+// so a sheet is never held whole in memory
+func readXlsxSheet(file *zip.File, sharedStrings xlsxSharedStrings, limit Limit) ([][]string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("read xlsx %s : %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	limited := &io.LimitedReader{R: reader, N: maxXlsxPartSize + 1}
+	decoder := xml.NewDecoder(limited)
+
+	limiter := rowLimiter{limit: limit}
+	rows := [][]string{}
+	var cells []string
+	inRow := false
+	rowCount, cellCount := 0, 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if limited.N <= 0 {
+				return nil, fmt.Errorf("read xlsx %s : part is too large", file.Name)
+			}
+			return nil, fmt.Errorf("read xlsx %s : %w", file.Name, err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "row":
+				rowCount++
+				rowNumber := rowCount
+				for _, attr := range element.Attr {
+					if attr.Name.Local != "r" {
+						continue
+					}
+					rowNumber, err = strconv.Atoi(attr.Value)
+					if err != nil || rowNumber <= 0 {
+						return nil, fmt.Errorf("read xlsx : row %s is invalid", attr.Value)
+					}
+				}
+				if rowNumber > maxXlsxRow {
+					return nil, fmt.Errorf("read xlsx : row %d is out of the sheet", rowNumber)
+				}
+				if err := limiter.checkRow(rowNumber); err != nil {
+					return nil, err
+				}
+				for len(rows) < rowNumber-1 {
+					rows = append(rows, []string{})
+				}
+				cells = []string{}
+				cellCount = 0
+				inRow = true
+
+			case "c":
+				if !inRow {
+					continue
+				}
+				cell := xlsxCell{}
+				if err := decoder.DecodeElement(&cell, &element); err != nil {
+					return nil, fmt.Errorf("read xlsx %s : %w", file.Name, err)
+				}
+
+				column := cellCount
+				cellCount++
+				if cell.Ref != "" {
+					column = columnIndex(cell.Ref)
+				}
+				if column < 0 || column >= maxXlsxColumn {
+					return nil, fmt.Errorf("read xlsx : cell %s is out of the sheet", cell.Ref)
+				}
+
+				value, err := cellValue(cell, sharedStrings)
+				if err != nil {
+					return nil, err
+				}
+				keep, err := limiter.keepCell(column, strings.TrimSpace(value))
+				if err != nil {
+					return nil, err
+				}
+				if !keep {
+					continue
+				}
+
+				for len(cells) < column {
+					cells = append(cells, "")
+				}
+				if column < len(cells) {
+					cells[column] = value
+				} else {
+					cells = append(cells, value)
+				}
+			}
+
+		case xml.EndElement:
+			if element.Name.Local == "row" && inRow {
+				row := trimRow(cells)
+				limiter.endRow(row)
+				rows = append(rows, row)
+				inRow = false
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+func cellValue(cell xlsxCell, sharedStrings xlsxSharedStrings) (string, error) {
+	switch cell.Type {
+	case "s":
+		index, err := strconv.Atoi(cell.Value)
+		if err != nil || index < 0 || index >= len(sharedStrings.Items) {
+			return "", fmt.Errorf("read xlsx : cell %s refer to a missing shared string", cell.Ref)
+		}
+		return sharedStrings.Items[index].String(), nil
+	case "inlineStr":
+		return cell.InlineStr.String(), nil
+	case "b":
+		return strconv.FormatBool(cell.Value == "1"), nil
+	}
+	return cell.Value, nil
+}
+
+// firstSheetPath follow the relationship of the first sheet of the workbook,
+// the usual path is used when the workbook can not be read
+func firstSheetPath(files map[string]*zip.File) string {
+	const defaultPath = "xl/worksheets/sheet1.xml"
+
+	workbook := xlsxWorkbook{}
+	relationships := xlsxRelationships{}
+	workbookFile, workbookExist := files["xl/workbook.xml"]
+	relationshipsFile, relationshipsExist := files["xl/_rels/workbook.xml.rels"]
+	if !workbookExist || !relationshipsExist ||
+		decodeXlsxPart(workbookFile, &workbook) != nil ||
+		decodeXlsxPart(relationshipsFile, &relationships) != nil ||
+		len(workbook.Sheets) == 0 {
+		return defaultPath
+	}
+
+	for _, relationship := range relationships.Relationships {
+		if relationship.Id != workbook.Sheets[0].RelationId {
+			continue
+		}
+		if strings.HasPrefix(relationship.Target, "/") {
+			return strings.TrimPrefix(relationship.Target, "/")
+		}
+		return path.Join("xl", relationship.Target)
+	}
+	return defaultPath
+}
+
+func decodeXlsxPart(file *zip.File, target interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("read xlsx %s : %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	limited := &io.LimitedReader{R: reader, N: maxXlsxPartSize + 1}
+	if err := xml.NewDecoder(limited).Decode(target); err != nil {
+		return fmt.Errorf("read xlsx %s : %w", file.Name, err)
+	}
+	if limited.N <= 0 {
+		return fmt.Errorf("read xlsx %s : part is too large", file.Name)
+	}
+	return nil
+}
+
+// columnIndex turn the letters of a cell reference like AB12 into a zero based column
+func columnIndex(ref string) int {
+	index := 0
+	for _, char := range strings.ToUpper(ref) {
+		if char < 'A' || char > 'Z' || index > maxXlsxColumn {
+			break
+		}
+		index = index*26 + int(char-'A'+1)
+	}
+	return index - 1
+}
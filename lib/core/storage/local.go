@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+type localStorage struct {
+	dir     string
+	baseUrl string
+}
+
+func NewLocalStorage(dir string, baseUrl string) (Storage, error) {
+	if dir == "" {
+		return nil, errors.New("storage local dir must not empty")
+	}
+	return &localStorage{dir: dir, baseUrl: baseUrl}, nil
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return "", err
+	}
+
+	// written aside then renamed, a reader never see a partial file
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	return objectUrl(s.baseUrl, key), nil
+}
+
+// Delete ignore a missing object
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
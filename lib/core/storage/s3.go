@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// s3Storage speak the S3 rest api signed with AWS signature version 4,
+// it work with AWS and the compatible services like MinIO
+type s3Storage struct {
+	config     S3Config
+	endpoint   *url.URL
+	baseUrl    string
+	httpClient *http.Client
+}
+
+func NewS3Storage(config S3Config, baseUrl string) (Storage, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("storage s3 endpoint must not empty")
+	}
+	if config.Bucket == "" {
+		return nil, errors.New("storage s3 bucket must not empty")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("storage s3 endpoint %s must have a scheme and a host", config.Endpoint)
+	}
+
+	return &s3Storage{
+		config:     config,
+		endpoint:   endpoint,
+		baseUrl:    baseUrl,
+		httpClient: &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	if err := s.do(req, data); err != nil {
+		return "", err
+	}
+
+	return objectUrl(s.baseUrl, key), nil
+}
+
+// Delete succeed on a missing object, S3 answer 204 for it
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, nil)
+}
+
+func (s *s3Storage) newRequest(ctx context.Context, method, key string, data []byte) (*http.Request, error) {
+	requestUrl := *s.endpoint
+	escapedKey := escapePath(key)
+	if s.config.UsePathStyle {
+		requestUrl.Path = requestUrl.Path + "/" + s.config.Bucket + "/" + key
+		requestUrl.RawPath = s.endpoint.EscapedPath() + "/" + escapePath(s.config.Bucket) + "/" + escapedKey
+	} else {
+		requestUrl.Host = s.config.Bucket + "." + requestUrl.Host
+		requestUrl.Path = requestUrl.Path + "/" + key
+		requestUrl.RawPath = s.endpoint.EscapedPath() + "/" + escapedKey
+	}
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	return http.NewRequestWithContext(ctx, method, requestUrl.String(), body)
+}
+
+func (s *s3Storage) do(req *http.Request, data []byte) error {
+	s.sign(req, data, time.Now().UTC())
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("storage s3 %s %s answered %d : %s", req.Method, req.URL.Path, res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign add the AWS signature version 4 headers, the payload is hashed so it is covered by the signature
+func (s *s3Storage) sign(req *http.Request, data []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	payloadHash := sha256Hex(data)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, amzDate)
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signedHeaders = []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+		canonicalHeaders = "content-type:" + strings.TrimSpace(contentType) + "\n" + canonicalHeaders
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{shortDate, s.config.Region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSha256([]byte("AWS4"+s.config.SecretKey), shortDate)
+	signingKey = hmacSha256(signingKey, s.config.Region)
+	signingKey = hmacSha256(signingKey, "s3")
+	signingKey = hmacSha256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// escapePath encode the key the way S3 canonicalize it, only the unreserved characters and the slashes are kept
+func escapePath(key string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(key) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' || b == '/' {
+			escaped.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&escaped, "%%%02X", b)
+	}
+	return escaped.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,98 @@
+// Package storage keep the uploaded files, on the local disk or in an S3 compatible bucket
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Backends
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+var ErrInvalidKey = errors.New("storage key is invalid")
+
+// Storage save the object under its key and return the url it is served from,
+// the key is returned when the backend has no base url
+type Storage interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type (
+	Config struct {
+		// Backend is local or s3.
+		// Optional. Default value local.
+		Backend string `mapstructure:"backend"`
+
+		// BaseUrl is prefixed to the key to build the url of an object,
+		// e.g. http://127.0.0.1:3000/storage or the public url of the bucket.
+		// Optional. Default value empty, the key is returned.
+		BaseUrl string `mapstructure:"base_url"`
+
+		Local LocalConfig `mapstructure:"local"`
+		S3    S3Config    `mapstructure:"s3"`
+	}
+
+	LocalConfig struct {
+		// Dir is the directory the objects are written to.
+		// Required for the local backend.
+		Dir string `mapstructure:"dir"`
+
+		// ServePath is the route the directory is served from by the api, e.g. /storage.
+		// Optional. Default value empty, the directory is not served.
+		ServePath string `mapstructure:"serve_path"`
+	}
+
+	S3Config struct {
+		// Endpoint is the scheme and host of the service, e.g. https://s3.eu-west-1.amazonaws.com
+		// or http://127.0.0.1:9000 for MinIO.
+		// Required for the s3 backend.
+		Endpoint string `mapstructure:"endpoint"`
+
+		// Required for the s3 backend.
+		Bucket string `mapstructure:"bucket"`
+
+		// Optional. Default value us-east-1.
+		Region string `mapstructure:"region"`
+
+		AccessKey string `mapstructure:"access_key"`
+		SecretKey string `mapstructure:"secret_key"`
+
+		// UsePathStyle put the bucket in the path instead of the host, MinIO need it.
+		// Optional. Default value false.
+		UsePathStyle bool `mapstructure:"use_path_style"`
+	}
+)
+
+func NewStorage(config Config) (Storage, error) {
+	switch config.Backend {
+	case "", BackendLocal:
+		return NewLocalStorage(config.Local.Dir, config.BaseUrl)
+	case BackendS3:
+		return NewS3Storage(config.S3, config.BaseUrl)
+	}
+	return nil, fmt.Errorf("unsupported storage backend %s", config.Backend)
+}
+
+// cleanKey reject the keys going out of the storage root
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// objectUrl return the key when there is no base url
+func objectUrl(baseUrl, key string) string {
+	if baseUrl == "" {
+		return key
+	}
+	return strings.TrimSuffix(baseUrl, "/") + "/" + key
+}
@@ -0,0 +1,137 @@
+// Package thumbnail create the square thumbnails of an uploaded image with the standard library only,
+// jpeg stay jpeg and png or gif become png
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// Content types
+const (
+	ContentTypeJpeg = "image/jpeg"
+	ContentTypePng  = "image/png"
+	ContentTypeGif  = "image/gif"
+)
+
+// MaxPixels is the largest decoded image accepted, a small compressed file can decode to a huge image
+const MaxPixels = 40_000_000
+
+var (
+	ErrUnsupportedFormat = errors.New("image format is not supported")
+	ErrImageTooLarge     = errors.New("image dimension is too large")
+)
+
+type Image struct {
+	Size        int
+	ContentType string
+	Data        []byte
+}
+
+// Generate return a thumbnail for every size, the image is cropped to its center square then scaled down.
+// An image smaller than the size is not enlarged
+func Generate(data []byte, sizes []int) ([]Image, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrUnsupportedFormat
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	// a truncated or corrupted file is refused like an unknown format
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	square := cropSquare(decoded)
+
+	thumbnails := make([]Image, 0, len(sizes))
+	for _, size := range sizes {
+		if size <= 0 {
+			continue
+		}
+
+		var buf bytes.Buffer
+		contentType, err := encode(&buf, resize(square, size), format)
+		if err != nil {
+			return nil, err
+		}
+
+		thumbnails = append(thumbnails, Image{
+			Size:        size,
+			ContentType: contentType,
+			Data:        buf.Bytes(),
+		})
+	}
+
+	return thumbnails, nil
+}
+
+func encode(buf *bytes.Buffer, img image.Image, format string) (string, error) {
+	switch format {
+	case "jpeg":
+		return ContentTypeJpeg, jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+	case "png", "gif":
+		return ContentTypePng, png.Encode(buf, img)
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// cropSquare copy the center square to an RGBA image, the pixels are then read directly
+func cropSquare(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	origin := image.Point{
+		X: bounds.Min.X + (bounds.Dx()-side)/2,
+		Y: bounds.Min.Y + (bounds.Dy()-side)/2,
+	}
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), img, origin, draw.Src)
+	return square
+}
+
+// resize average the source pixels covered by every destination pixel, it is only used to scale down
+func resize(src *image.RGBA, size int) *image.RGBA {
+	srcSize := src.Bounds().Dx()
+	if size >= srcSize {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*srcSize/size, (y+1)*srcSize/size
+		for x := 0; x < size; x++ {
+			x0, x1 := x*srcSize/size, (x+1)*srcSize/size
+
+			var r, g, b, a, count int
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[offset])
+					g += int(src.Pix[offset+1])
+					b += int(src.Pix[offset+2])
+					a += int(src.Pix[offset+3])
+					offset += 4
+					count++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / count)
+			dst.Pix[offset+1] = uint8(g / count)
+			dst.Pix[offset+2] = uint8(b / count)
+			dst.Pix[offset+3] = uint8(a / count)
+		}
+	}
+	return dst
+}
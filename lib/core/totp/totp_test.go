@@ -0,0 +1,80 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed "12345678901234567890" of RFC 6238 appendix B in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeAtRFC6238(t *testing.T) {
+	// the RFC list 8 digits codes, a 6 digits code is made of their last 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := CodeAt(rfc6238Secret, Counter(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("CodeAt(%d) error : %v", tt.unix, err)
+		}
+		if got != tt.code {
+			t.Errorf("CodeAt(%d) = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestCodeAtInvalidSecret(t *testing.T) {
+	if _, err := CodeAt("not base32 !", 1); err == nil {
+		t.Error("CodeAt with an invalid secret must return an error")
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Counter(now)
+
+	codeAt := func(counter int64) string {
+		code, err := CodeAt(rfc6238Secret, counter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name        string
+		code        string
+		skew        int
+		wantCounter int64
+		wantOk      bool
+	}{
+		{"current step", codeAt(current), 0, current, true},
+		{"previous step without skew", codeAt(current - 1), 0, 0, false},
+		{"previous step with skew", codeAt(current - 1), 1, current - 1, true},
+		{"next step with skew", codeAt(current + 1), 1, current + 1, true},
+		{"two steps before with skew 1", codeAt(current - 2), 1, 0, false},
+		{"two steps after with skew 2", codeAt(current + 2), 2, current + 2, true},
+		{"surrounding spaces", " " + codeAt(current) + " ", 0, current, true},
+		{"too short", codeAt(current)[:5], 1, 0, false},
+		{"wrong code", "000000", 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, ok := Validate(rfc6238Secret, tt.code, now, tt.skew)
+			if ok != tt.wantOk || counter != tt.wantCounter {
+				t.Errorf("Validate() = (%d, %v), want (%d, %v)", counter, ok, tt.wantCounter, tt.wantOk)
+			}
+		})
+	}
+}
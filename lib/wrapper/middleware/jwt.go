@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"reflect"
 
-	"github.com/dgrijalva/jwt-go"
+	"backend_base_app/lib/core/jwtkey"
+
+	"github.com/golang-jwt/jwt/v4"
 )
 
 type (
@@ -59,6 +61,15 @@ var (
 )
 
 func GetMapClaimByKeyJwtToken(secret, auth, key string) (interface{}, error) {
+	claims, err := GetMapClaimsJwtToken(secret, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims[key], nil
+}
+
+func GetMapClaimsJwtToken(secret, auth string) (jwt.MapClaims, error) {
 	var err error
 
 	config := JWTConfig{
@@ -93,5 +104,27 @@ func GetMapClaimByKeyJwtToken(secret, auth, key string) (interface{}, error) {
 
 	claims := token.Claims.(jwt.MapClaims)
 
-	return claims[key], nil
+	return claims, nil
+}
+
+// ParseJwtTokenWithKeySet validate the token with the key chosen by its kid header
+// and decode it into claims
+func ParseJwtTokenWithKeySet(keySet *jwtkey.KeySet, auth string, claims jwt.Claims) error {
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := keySet.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		// Check the signing method
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("Unexpected jwt signing method=%v", t.Header["alg"])
+		}
+		return key.PublicKey, nil
+	}
+
+	_, err := jwt.ParseWithClaims(auth, claims, keyFunc)
+
+	return err
 }
@@ -1,5 +1,14 @@
 package middleware
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
 type (
 	// KeyAuthConfig defines the config for KeyAuth middleware.
 	KeyAuthConfig struct {
@@ -11,18 +20,125 @@ type (
 		// - "header:<name>"
 		// - "query:<name>"
 		// - "form:<name>"
-		KeyLookup string `header:Authorization"`
+		KeyLookup string
 
 		// AuthScheme to be used in the Authorization header.
 		// Optional. Default value "Bearer".
 		AuthScheme string
+
+		// Validator is a function to validate key.
+		// Required.
+		Validator KeyAuthValidator
+
+		// ErrorHandler is called when the key is missing or invalid, it must abort the request.
+		// Optional. Default value abort with status 401.
+		ErrorHandler KeyAuthErrorHandler
 	}
 
+	// KeyAuthValidator defines a function to validate KeyAuth credentials.
+	KeyAuthValidator func(c *gin.Context, key string) (bool, error)
+
+	// KeyAuthErrorHandler defines a function which is executed when the key is missing or invalid.
+	KeyAuthErrorHandler func(c *gin.Context, err error)
 )
 
 var (
 	// DefaultKeyAuthConfig is the default KeyAuth middleware config.
 	DefaultKeyAuthConfig = KeyAuthConfig{
+		KeyLookup:  "header:Authorization",
 		AuthScheme: "Bearer",
 	}
 )
+
+var (
+	ErrKeyAuthMissing = errors.New("missing key in the request")
+	ErrKeyAuthInvalid = errors.New("invalid key")
+)
+
+// KeyAuth returns an KeyAuth middleware.
+//
+// For valid key it calls the next handler.
+// For invalid key, it sends "401 - Unauthorized" response.
+// For missing key, it sends "400 - Bad Request" response.
+func KeyAuth(fn KeyAuthValidator) gin.HandlerFunc {
+	c := DefaultKeyAuthConfig
+	c.Validator = fn
+	return KeyAuthWithConfig(c)
+}
+
+// KeyAuthWithConfig returns an KeyAuth middleware with config.
+// See `KeyAuth()`.
+func KeyAuthWithConfig(config KeyAuthConfig) gin.HandlerFunc {
+	// Defaults
+	if config.AuthScheme == "" {
+		config.AuthScheme = DefaultKeyAuthConfig.AuthScheme
+	}
+	if config.KeyLookup == "" {
+		config.KeyLookup = DefaultKeyAuthConfig.KeyLookup
+	}
+	if config.Validator == nil {
+		panic("key-auth middleware requires a validator function")
+	}
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = defaultKeyAuthErrorHandler
+	}
+
+	return func(c *gin.Context) {
+		key, err := ExtractKey(c, config.KeyLookup, config.AuthScheme)
+		if err != nil {
+			config.ErrorHandler(c, err)
+			return
+		}
+
+		valid, err := config.Validator(c, key)
+		if err != nil {
+			config.ErrorHandler(c, err)
+			return
+		}
+		if !valid {
+			config.ErrorHandler(c, ErrKeyAuthInvalid)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ExtractKey read the key from the source of the lookup, the auth scheme is only used with the Authorization header
+func ExtractKey(c *gin.Context, keyLookup, authScheme string) (string, error) {
+	parts := strings.SplitN(keyLookup, ":", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid key lookup %s", keyLookup)
+	}
+
+	var key string
+	switch parts[0] {
+	case "query":
+		key = c.Query(parts[1])
+	case "form":
+		key = c.PostForm(parts[1])
+	default:
+		key = c.GetHeader(parts[1])
+		if parts[1] == "Authorization" {
+			prefix := authScheme + " "
+			if !strings.HasPrefix(key, prefix) {
+				return "", ErrKeyAuthMissing
+			}
+			key = key[len(prefix):]
+		}
+	}
+
+	if key == "" {
+		return "", ErrKeyAuthMissing
+	}
+
+	return key, nil
+}
+
+func defaultKeyAuthErrorHandler(c *gin.Context, err error) {
+	if errors.Is(err, ErrKeyAuthMissing) {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	c.AbortWithStatus(http.StatusUnauthorized)
+}
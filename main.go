@@ -11,7 +11,10 @@ func main() {
 	fmt.Printf(" - %s\n", "appName")
 
 	appMap := map[string]func() application.RegistryContract{
-		"api_base_app": registry.ApiBaseApp(),
+		"api_base_app":  registry.ApiBaseApp(),
+		"mock_idp":      registry.MockIdp(),
+		"audit_export":  registry.AuditExport(),
+		"member_import": registry.MemberImport(),
 	}
 
 	flag.Parse()
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"backend_base_app/lib/core/jwtkey"
+
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
 )
 
 type JwtClaims struct {
@@ -19,6 +21,7 @@ func (u HTTPHelper) CreateJwtToken(secret string, apiToken string, confidentialM
 	claims := JwtClaims{
 		jwt.StandardClaims{
 			Id:        apiToken,
+			IssuedAt:  time.Now().Unix(),
 			ExpiresAt: time.Now().Add(time.Duration(confidentialMinute) * time.Minute).Unix(),
 		},
 	}
@@ -33,6 +36,21 @@ func (u HTTPHelper) CreateJwtToken(secret string, apiToken string, confidentialM
 	return token, err
 }
 
+// CreateJwtTokenWithKey sign the claims with the key algorithm and put its kid in the header
+func (u HTTPHelper) CreateJwtTokenWithKey(key *jwtkey.Key, claims jwt.Claims) (string, error) {
+	rawToken := jwt.NewWithClaims(key.Method, claims)
+	if key.Kid != "" {
+		rawToken.Header["kid"] = key.Kid
+	}
+
+	token, err := rawToken.SignedString(key.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return token, err
+}
+
 func (u HTTPHelper) GetJwtClaims(c *gin.Context) jwt.MapClaims {
 	user, _ := c.Get("user")
 	if user == nil {
@@ -112,3 +130,19 @@ func (u HTTPHelper) GetJsonClaimFromContext(c *gin.Context) (string, error) {
 
 	return userFromContext.(string), nil
 }
+
+// SetMemberClaims store the claims of the token validated by the interceptor
+func (u HTTPHelper) SetMemberClaims(c *gin.Context, claims *MemberClaims) {
+	c.Set(memberClaimsContextKey, claims)
+}
+
+// GetMemberClaimsFromContext return the claims stored by SetMemberClaims
+func (u HTTPHelper) GetMemberClaimsFromContext(c *gin.Context) (*MemberClaims, error) {
+	claimsFromContext, _ := c.Get(memberClaimsContextKey)
+	claims, ok := claimsFromContext.(*MemberClaims)
+	if !ok || claims == nil {
+		return nil, fmt.Errorf("token claims not exist in member claims context")
+	}
+
+	return claims, nil
+}
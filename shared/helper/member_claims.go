@@ -0,0 +1,150 @@
+package helper
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Token types, a refresh token must never be accepted as an access token
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	// TokenTypeTwoFactorChallenge is returned by the login when the second factor is still needed
+	TokenTypeTwoFactorChallenge = "2fa_challenge"
+)
+
+const memberClaimsContextKey = "member_claims"
+
+var (
+	ErrInvalidTokenType     = errors.New("token type is invalid")
+	ErrInvalidTokenIssuer   = errors.New("token issuer is invalid")
+	ErrInvalidTokenAudience = errors.New("token audience is invalid")
+)
+
+// ActorClaim is the act claim of RFC 8693, Subject is the admin acting as the member
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
+// MemberClaims are the claims of the member access and refresh token.
+// Subject is the member id, ID is a random token id and SessionId is the session the token belongs to.
+// An impersonation token carry Act and the session of the admin.
+// AuthTime and Amr are the last authentication of the session, they are checked by the step-up routes.
+// CredentialVersion is the credential version of the member when the token was issued
+type MemberClaims struct {
+	jwt.RegisteredClaims
+	DeviceId  string           `json:"device_id,omitempty"`
+	SessionId string           `json:"sid,omitempty"`
+	Roles     []string         `json:"roles,omitempty"`
+	Type      string           `json:"typ"`
+	Act       *ActorClaim      `json:"act,omitempty"`
+	AuthTime  *jwt.NumericDate `json:"auth_time,omitempty"`
+	Amr       []string         `json:"amr,omitempty"`
+
+	CredentialVersion int `json:"cv,omitempty"`
+}
+
+type MemberClaimsReq struct {
+	TokenId            string
+	MemberId           string
+	DeviceId           string
+	SessionId          string
+	Roles              []string
+	Type               string
+	ActorId            string
+	AuthTime           time.Time
+	Amr                []string
+	CredentialVersion  int
+	Issuer             string
+	Audience           string
+	ConfidentialMinute int
+}
+
+func NewMemberClaims(req MemberClaimsReq) MemberClaims {
+	now := time.Now()
+
+	claims := MemberClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        req.TokenId,
+			Subject:   req.MemberId,
+			Issuer:    req.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(req.ConfidentialMinute) * time.Minute)),
+		},
+		DeviceId:  req.DeviceId,
+		SessionId: req.SessionId,
+		Roles:     req.Roles,
+		Type:      req.Type,
+
+		CredentialVersion: req.CredentialVersion,
+	}
+
+	if req.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{req.Audience}
+	}
+	if req.ActorId != "" {
+		claims.Act = &ActorClaim{Subject: req.ActorId}
+	}
+	if !req.AuthTime.IsZero() {
+		claims.AuthTime = jwt.NewNumericDate(req.AuthTime)
+		claims.Amr = req.Amr
+	}
+
+	return claims
+}
+
+// VerifyToken check the claims which are not covered by Valid,
+// issuer and audience are only checked when they are configured
+func (c MemberClaims) VerifyToken(tokenType, issuer, audience string) error {
+	if c.Type != tokenType {
+		return ErrInvalidTokenType
+	}
+	if issuer != "" && !c.VerifyIssuer(issuer, true) {
+		return ErrInvalidTokenIssuer
+	}
+	if audience != "" && !c.VerifyAudience(audience, true) {
+		return ErrInvalidTokenAudience
+	}
+	return nil
+}
+
+// GetIssuedAt return zero time for token without iat, so they are always older than any watermark
+func (c MemberClaims) GetIssuedAt() time.Time {
+	if c.IssuedAt == nil {
+		return time.Time{}
+	}
+	return c.IssuedAt.Time
+}
+
+// GetExpiresAt return zero time for token without exp
+func (c MemberClaims) GetExpiresAt() time.Time {
+	if c.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return c.ExpiresAt.Time
+}
+
+// GetAuthTime return zero time for token without auth_time, like the tokens of a session
+// started before the claim existed, so they always need a reauthentication
+func (c MemberClaims) GetAuthTime() time.Time {
+	if c.AuthTime == nil {
+		return time.Time{}
+	}
+	return c.AuthTime.Time
+}
+
+// IsImpersonated is true when an admin use the token to act as the member
+func (c MemberClaims) IsImpersonated() bool {
+	return c.Act != nil && c.Act.Subject != ""
+}
+
+// GetActorId return the admin acting as the member, empty when the member use its own token
+func (c MemberClaims) GetActorId() string {
+	if !c.IsImpersonated() {
+		return ""
+	}
+	return c.Act.Subject
+}
@@ -0,0 +1,10 @@
+package checkapikeyv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, key string) (*entity.ApiKeyData, error)
+}
@@ -0,0 +1,61 @@
+package checkapikeyv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+)
+
+type apibaseappapikeycheckInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappapikeycheckInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute return the key data when the key is valid, and record its last use
+func (r *apibaseappapikeycheckInteractor) Execute(ctx context.Context, key string) (*entity.ApiKeyData, error) {
+	response := &entity.ApiKeyData{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		prefix, ok := entity.ApiKeyPrefixOf(key)
+		if !ok {
+			return entity.ApiKeyInvalid
+		}
+
+		apiKey, err := r.outport.FindOneApiKeyByPrefix(ctx, prefix)
+		if err != nil {
+			return err
+		}
+
+		if !apiKey.MatchKey(key) {
+			return entity.ApiKeyInvalid
+		}
+
+		err = apiKey.CheckActive(time.Now())
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.TouchApiKey(ctx, apiKey.ID)
+		if err != nil {
+			// the request is allowed anyway, the last use is informative
+			log.Error(ctx, err.Error())
+		}
+
+		response = apiKey
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
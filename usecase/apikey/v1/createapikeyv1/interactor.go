@@ -0,0 +1,74 @@
+package createapikeyv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappapikeycreateInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappapikeycreateInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappapikeycreateInteractor) Execute(ctx context.Context, req entity.CreateApiKeyReq) (*entity.CreateApiKeyRes, error) {
+	res := &entity.CreateApiKeyRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		err := req.Validate()
+		if err != nil {
+			return err
+		}
+
+		err = r.validateScopes(ctx, req.Scopes)
+		if err != nil {
+			return err
+		}
+
+		apiKey, key, err := entity.NewApiKeyData(req)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.CreateApiKey(ctx, *apiKey)
+		if err != nil {
+			return err
+		}
+
+		res.ApiKeyData = *apiKey
+		res.Key = key
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// validateScopes only accept registered permissions, the same as a role
+func (r *apibaseappapikeycreateInteractor) validateScopes(ctx context.Context, scopes []string) error {
+	res, err := r.outport.FindAllPermissionByIds(ctx, scopes)
+	if err != nil {
+		return err
+	}
+
+	registered := map[string]bool{}
+	for _, permission := range res {
+		registered[permission.ID] = true
+	}
+	for _, scope := range scopes {
+		if !registered[scope] {
+			return entity.PermissionNotFound.Var(scope)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package getallapikeyv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.BaseReqFind) ([]entity.ApiKeyData, int64, error)
+}
@@ -0,0 +1,38 @@
+package getallapikeyv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappapikeygetallInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappapikeygetallInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappapikeygetallInteractor) Execute(ctx context.Context, req entity.BaseReqFind) ([]entity.ApiKeyData, int64, error) {
+	var response = []entity.ApiKeyData{}
+	var totalRecords = int64(-1)
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, count, err := r.outport.FindAllApiKey(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, apiKey := range res {
+			response = append(response, *apiKey)
+		}
+
+		totalRecords = count
+
+		return nil
+	})
+	return response, totalRecords, err
+}
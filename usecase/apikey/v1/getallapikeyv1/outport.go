@@ -0,0 +1,11 @@
+package getallapikeyv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.ApiKeyRepo
+	dbhelpers.WithoutTransactionDB
+}
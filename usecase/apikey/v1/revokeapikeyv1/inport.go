@@ -0,0 +1,9 @@
+package revokeapikeyv1
+
+import (
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, id string) error
+}
@@ -0,0 +1,23 @@
+package revokeapikeyv1
+
+import (
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappapikeyrevokeInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappapikeyrevokeInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute keep the revoked key, so it is still listed with its last use
+func (r *apibaseappapikeyrevokeInteractor) Execute(ctx context.Context, id string) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+		return r.outport.RevokeApiKey(ctx, id)
+	})
+}
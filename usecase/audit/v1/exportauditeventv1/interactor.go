@@ -0,0 +1,59 @@
+package exportauditeventv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+// exportBatchSize is the number of events read at once
+const exportBatchSize = 500
+
+type apibaseappexportauditeventInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappexportauditeventInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute walk the chain from the first event and stop at the first broken link,
+// the events before it are already written
+func (r *apibaseappexportauditeventInteractor) Execute(ctx context.Context, req entity.AuditExportReq) (*entity.AuditExportRes, error) {
+	res := &entity.AuditExportRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		var previous *entity.AuditEventData
+		for {
+			events, err := r.outport.FindAllAuditEventAfterSequence(ctx, res.LastSequence, exportBatchSize)
+			if err != nil {
+				return err
+			}
+			if len(events) == 0 {
+				return nil
+			}
+
+			for _, event := range events {
+				err = event.VerifyChain(previous)
+				if err != nil {
+					return err
+				}
+
+				err = req.Write(*event)
+				if err != nil {
+					return err
+				}
+
+				previous = event
+				res.Count++
+				res.LastSequence = event.Sequence
+				res.LastHash = event.Hash
+			}
+		}
+	})
+
+	return res, err
+}
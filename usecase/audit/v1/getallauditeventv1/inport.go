@@ -0,0 +1,10 @@
+package getallauditeventv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.BaseReqFind) ([]entity.AuditEventData, int64, error)
+}
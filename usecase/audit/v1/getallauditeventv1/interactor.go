@@ -0,0 +1,38 @@
+package getallauditeventv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappauditeventgetallInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappauditeventgetallInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappauditeventgetallInteractor) Execute(ctx context.Context, req entity.BaseReqFind) ([]entity.AuditEventData, int64, error) {
+	var response = []entity.AuditEventData{}
+	var totalRecords = int64(-1)
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, count, err := r.outport.FindAllAuditEvent(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, auditEvent := range res {
+			response = append(response, *auditEvent)
+		}
+
+		totalRecords = count
+
+		return nil
+	})
+	return response, totalRecords, err
+}
@@ -0,0 +1,38 @@
+package recordauditeventv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapprecordauditeventInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapprecordauditeventInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapprecordauditeventInteractor) Execute(ctx context.Context, req entity.AuditEventReq) (*entity.AuditEventData, error) {
+	res := &entity.AuditEventData{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		auditEvent, err := r.outport.AppendAuditEvent(ctx, entity.NewAuditEventData(req))
+		if err != nil {
+			return err
+		}
+
+		res = auditEvent
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,11 @@
+package recordauditeventv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.AuditEventRepo
+	dbhelpers.WithoutTransactionDB
+}
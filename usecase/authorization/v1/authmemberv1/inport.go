@@ -6,5 +6,5 @@ import (
 )
 
 type Inport interface {
-	Execute(ctx context.Context, req entity.MemberReqAuth) (*entity.MemberDataShown, error)
+	Execute(ctx context.Context, req entity.MemberReqAuth) (*entity.MemberLoginRes, error)
 }
@@ -2,8 +2,12 @@ package authmemberv1
 
 import (
 	"backend_base_app/domain/entity"
+	"backend_base_app/gateway/apibaseappgateway"
 	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
 	"context"
+	"errors"
+	"time"
 )
 
 type apibaseappmembercreateInteractor struct {
@@ -16,16 +20,55 @@ func NewUsecase(outputPort Outport) Inport {
 	}
 }
 
-func (r *apibaseappmembercreateInteractor) Execute(ctx context.Context, req entity.MemberReqAuth) (*entity.MemberDataShown, error) {
-	response := &entity.MemberDataShown{}
+func (r *apibaseappmembercreateInteractor) Execute(ctx context.Context, req entity.MemberReqAuth) (*entity.MemberLoginRes, error) {
+	response := &entity.MemberLoginRes{}
 
 	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
-		res, err := r.outport.MemberLoginAuthorization(ctx, req)
+		if req.LoginProtection.Enabled {
+			err := r.checkLoginAttempt(ctx, req)
+			if err != nil {
+				return err
+			}
+		}
+
+		res, err := r.outport.VerifyMemberCredential(ctx, req)
+		if err != nil {
+			if req.LoginProtection.Enabled && errors.Is(err, apibaseappgateway.InvalidUsernameOrPassword) {
+				r.recordFailedLoginAttempt(ctx, req)
+			}
+			return err
+		}
+
+		err = res.CheckVerified(req.GetRequiredVerification(res.MemberType))
+		if err != nil {
+			return err
+		}
+
+		// the login is completed by the second factor, the username counter is kept until then
+		twoFactor, err := r.outport.FindOneTwoFactorByMemberId(ctx, res.ID)
+		if err != nil && !errors.Is(err, entity.TwoFactorNotEnrolled) {
+			return err
+		}
+		if twoFactor != nil && twoFactor.IsEnabled {
+			response.Member = *res
+			response.TwoFactorRequired = true
+			return nil
+		}
+
+		if req.LoginProtection.Enabled {
+			// only the username counter is reset, a valid account must not unlock its whole ip
+			err = r.outport.DeleteLoginAttempt(ctx, entity.LoginAttemptId(entity.LoginAttemptKindUsername, req.Username))
+			if err != nil {
+				log.Error(ctx, err.Error())
+			}
+		}
+
+		member, err := r.outport.CompleteMemberLogin(ctx, *res, req.DeviceId, req.TokenBroadcast)
 		if err != nil {
 			return err
 		}
 
-		response = res
+		response.Member = *member
 
 		return nil
 	})
@@ -35,3 +78,32 @@ func (r *apibaseappmembercreateInteractor) Execute(ctx context.Context, req enti
 
 	return response, nil
 }
+
+// checkLoginAttempt return LoginLockedError when the username or the ip has to wait
+func (r *apibaseappmembercreateInteractor) checkLoginAttempt(ctx context.Context, req entity.MemberReqAuth) error {
+	attempts, err := r.outport.FindAllLoginAttemptByIds(ctx, entity.LoginAttemptIds(req.Username, req.IpAddress))
+	if err != nil {
+		return err
+	}
+
+	return entity.CheckLoginAttempts(attempts, time.Now())
+}
+
+// recordFailedLoginAttempt only log its error, so the member still get the invalid password error
+func (r *apibaseappmembercreateInteractor) recordFailedLoginAttempt(ctx context.Context, req entity.MemberReqAuth) {
+	now := time.Now()
+	for kind, key := range entity.LoginAttemptKeys(req.Username, req.IpAddress) {
+		attempt, err := r.outport.IncrementLoginAttempt(ctx, entity.NewLoginAttemptData(kind, key, now.Add(req.LoginProtection.Window)))
+		if err != nil {
+			log.Error(ctx, err.Error())
+			continue
+		}
+
+		attempt.ApplyFailure(req.LoginProtection, now)
+
+		err = r.outport.UpdateLoginAttempt(ctx, *attempt)
+		if err != nil {
+			log.Error(ctx, err.Error())
+		}
+	}
+}
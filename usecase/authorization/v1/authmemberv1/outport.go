@@ -9,5 +9,7 @@ import (
 type Outport interface {
 	service.EncryptPasswordService
 	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.LoginAttemptRepo
+	apibaseappgateway.TwoFactorRepo
 	dbhelpers.WithoutTransactionDB
 }
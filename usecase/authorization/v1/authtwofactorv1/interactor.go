@@ -0,0 +1,128 @@
+package authtwofactorv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+)
+
+type apibaseappauthtwofactorInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappauthtwofactorInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappauthtwofactorInteractor) Execute(ctx context.Context, req entity.TwoFactorLoginReq) (*entity.MemberDataShown, error) {
+	response := &entity.MemberDataShown{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		// a challenge is revoked with the other tokens of the member
+		isRevoked, err := r.outport.IsTokenRevoked(ctx, entity.CheckRevokedTokenReq{
+			MemberId: req.MemberId,
+			TokenId:  req.ChallengeId,
+			IssuedAt: req.ChallengeIssuedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if isRevoked {
+			return entity.TokenHasBeenRevoked
+		}
+
+		// a challenge is used once, it is consumed before the code is checked so concurrent requests
+		// can not both use it. A wrong code need a new login
+		err = r.outport.ConsumeToken(ctx, entity.NewRevokedTokenData(req.MemberId, req.ChallengeId, req.ChallengeExpiredAt))
+		if err != nil {
+			return err
+		}
+
+		member, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		if member.IsSuspend {
+			return entity.NewMyError("Account is Suspended")
+		}
+
+		if req.LoginProtection.Enabled {
+			attempts, err := r.outport.FindAllLoginAttemptByIds(ctx, entity.LoginAttemptIds(member.Username, req.IpAddress))
+			if err != nil {
+				return err
+			}
+			err = entity.CheckLoginAttempts(attempts, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		twoFactor, err := r.outport.FindOneTwoFactorByMemberId(ctx, member.ID)
+		if err != nil {
+			return err
+		}
+		if !twoFactor.IsEnabled {
+			return entity.TwoFactorNotEnrolled
+		}
+
+		codeUse, ok := twoFactor.VerifyCode(req.Code, req.Config, time.Now())
+		if !ok {
+			if req.LoginProtection.Enabled {
+				r.recordFailedLoginAttempt(ctx, member.Username, req)
+			}
+			return entity.TwoFactorCodeInvalid
+		}
+
+		err = r.outport.UseTwoFactorCode(ctx, codeUse)
+		if err != nil {
+			return err
+		}
+
+		if req.LoginProtection.Enabled {
+			err = r.outport.DeleteLoginAttempt(ctx, entity.LoginAttemptId(entity.LoginAttemptKindUsername, member.Username))
+			if err != nil {
+				log.Error(ctx, err.Error())
+			}
+		}
+
+		res, err := r.outport.CompleteMemberLogin(ctx, *member, req.DeviceId, req.TokenBroadcast)
+		if err != nil {
+			return err
+		}
+
+		response = res
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// recordFailedLoginAttempt count a wrong code as a failed login, so the code can not be guessed
+// by repeating the password step. Its error is only logged
+func (r *apibaseappauthtwofactorInteractor) recordFailedLoginAttempt(ctx context.Context, username string, req entity.TwoFactorLoginReq) {
+	now := time.Now()
+	for kind, key := range entity.LoginAttemptKeys(username, req.IpAddress) {
+		attempt, err := r.outport.IncrementLoginAttempt(ctx, entity.NewLoginAttemptData(kind, key, now.Add(req.LoginProtection.Window)))
+		if err != nil {
+			log.Error(ctx, err.Error())
+			continue
+		}
+
+		attempt.ApplyFailure(req.LoginProtection, now)
+
+		err = r.outport.UpdateLoginAttempt(ctx, *attempt)
+		if err != nil {
+			log.Error(ctx, err.Error())
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package authtwofactorv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.LoginAttemptRepo
+	apibaseappgateway.TwoFactorRepo
+	apibaseappgateway.RevokedTokenRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,10 @@
+package changepasswordv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.ChangePasswordReq) (*entity.ChangePasswordRes, error)
+}
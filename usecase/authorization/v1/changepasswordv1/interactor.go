@@ -0,0 +1,168 @@
+package changepasswordv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"errors"
+	"time"
+)
+
+type apibaseappchangepasswordInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappchangepasswordInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute set the new password, the credential version of the member is increased so every token
+// issued before is rejected. The current session get a new refresh token when it is kept
+func (r *apibaseappchangepasswordInteractor) Execute(ctx context.Context, req entity.ChangePasswordReq) (*entity.ChangePasswordRes, error) {
+	res := &entity.ChangePasswordRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		member, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		// the current password is checked like a login, so it can not be guessed with a stolen token
+		if req.LoginProtection.Enabled {
+			attempts, err := r.outport.FindAllLoginAttemptByIds(ctx, entity.LoginAttemptIds(member.Username, req.IpAddress))
+			if err != nil {
+				return err
+			}
+			err = entity.CheckLoginAttempts(attempts, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = r.outport.VerifyMemberCredential(ctx, entity.MemberReqAuth{Username: member.Username, Password: req.CurrentPassword})
+		if err != nil {
+			if req.LoginProtection.Enabled && errors.Is(err, apibaseappgateway.InvalidUsernameOrPassword) {
+				r.recordFailedLoginAttempt(ctx, member.Username, req)
+			}
+			return err
+		}
+
+		err = r.outport.CheckPasswordPolicy(ctx, entity.CheckPasswordPolicyReq{
+			MemberId:   member.ID,
+			MemberType: member.MemberType,
+			Password:   req.NewPassword,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.UpdateMemberPassword(ctx, member.ID, req.NewPassword)
+		if err != nil {
+			return err
+		}
+
+		if req.LoginProtection.Enabled {
+			err = r.outport.DeleteLoginAttempt(ctx, entity.LoginAttemptId(entity.LoginAttemptKindUsername, member.Username))
+			if err != nil {
+				log.Error(ctx, err.Error())
+			}
+		}
+
+		if req.KeepCurrentSession {
+			err = r.keepCurrentSession(ctx, req, res)
+		} else {
+			err = r.outport.RevokeSessionByMemberId(ctx, member.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		// the member is read again for its new credential version
+		member, err = r.outport.FindOneMemberDataById(ctx, member.ID)
+		if err != nil {
+			return err
+		}
+
+		res.Member = *member
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// keepCurrentSession revoke the other sessions and start a new refresh token in the current one,
+// its previous refresh tokens carry the old credential version
+func (r *apibaseappchangepasswordInteractor) keepCurrentSession(ctx context.Context, req entity.ChangePasswordReq, res *entity.ChangePasswordRes) error {
+	sessions, err := r.outport.FindAllActiveSessionByMemberId(ctx, req.MemberId)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == req.SessionId {
+			continue
+		}
+		err = r.outport.RevokeSession(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	session, err := r.outport.FindOneSessionById(ctx, req.SessionId)
+	if err != nil {
+		return err
+	}
+	if session.MemberId != req.MemberId || !session.IsActive() {
+		return entity.SessionHasBeenRevoked
+	}
+
+	refreshToken := entity.NewRefreshTokenData(entity.CreateRefreshTokenData{
+		MemberId:  req.MemberId,
+		DeviceId:  session.DeviceId,
+		FamilyId:  session.ID,
+		ExpiredAt: req.RefreshTokenExpiredAt,
+	})
+
+	err = r.outport.CreateRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	err = r.outport.ExtendSession(ctx, session.ID, req.RefreshTokenExpiredAt)
+	if err != nil {
+		return err
+	}
+
+	res.Session = session
+	res.RefreshToken = &refreshToken
+
+	return nil
+}
+
+// recordFailedLoginAttempt only log its error, so the member still get the invalid password error
+func (r *apibaseappchangepasswordInteractor) recordFailedLoginAttempt(ctx context.Context, username string, req entity.ChangePasswordReq) {
+	now := time.Now()
+	for kind, key := range entity.LoginAttemptKeys(username, req.IpAddress) {
+		attempt, err := r.outport.IncrementLoginAttempt(ctx, entity.NewLoginAttemptData(kind, key, now.Add(req.LoginProtection.Window)))
+		if err != nil {
+			log.Error(ctx, err.Error())
+			continue
+		}
+
+		attempt.ApplyFailure(req.LoginProtection, now)
+
+		err = r.outport.UpdateLoginAttempt(ctx, *attempt)
+		if err != nil {
+			log.Error(ctx, err.Error())
+		}
+	}
+}
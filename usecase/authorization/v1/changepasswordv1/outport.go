@@ -0,0 +1,16 @@
+package changepasswordv1
+
+import (
+	"backend_base_app/domain/service"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	service.PasswordPolicyService
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.LoginAttemptRepo
+	apibaseappgateway.SessionRepo
+	apibaseappgateway.RefreshTokenRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,33 @@
+package checkrevokedtokenv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappcheckrevokedtokenInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappcheckrevokedtokenInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappcheckrevokedtokenInteractor) Execute(ctx context.Context, req entity.CheckRevokedTokenReq) (bool, error) {
+	var revoked bool
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, err := r.outport.IsTokenRevoked(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		revoked = res
+
+		return nil
+	})
+	return revoked, err
+}
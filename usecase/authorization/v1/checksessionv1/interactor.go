@@ -0,0 +1,55 @@
+package checksessionv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+)
+
+type apibaseappsessioncheckInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappsessioncheckInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappsessioncheckInteractor) Execute(ctx context.Context, req entity.CheckSessionReq) (*entity.SessionData, error) {
+	response := &entity.SessionData{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+		if req.SessionId == "" {
+			return entity.SessionNotFound
+		}
+
+		session, err := r.outport.FindOneSessionById(ctx, req.SessionId)
+		if err != nil {
+			return err
+		}
+
+		if session.MemberId != req.MemberId {
+			return entity.SessionNotFound
+		}
+
+		if !session.IsActive() {
+			return entity.SessionHasBeenRevoked
+		}
+
+		err = r.outport.TouchSession(ctx, session.ID)
+		if err != nil {
+			log.Error(ctx, err.Error())
+		}
+
+		response = session
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
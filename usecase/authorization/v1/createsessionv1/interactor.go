@@ -0,0 +1,81 @@
+package createsessionv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappsessioncreateInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappsessioncreateInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappsessioncreateInteractor) Execute(ctx context.Context, req entity.CreateSessionReq) (*entity.CreateSessionRes, error) {
+	response := &entity.CreateSessionRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		activeSessions, err := r.outport.FindAllActiveSessionByMemberId(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		// login again from the same device replace its previous session
+		remainSessions := make([]*entity.SessionData, 0, len(activeSessions))
+		for _, session := range activeSessions {
+			if req.DeviceId != "" && session.DeviceId == req.DeviceId {
+				err = r.outport.RevokeSession(ctx, session.ID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			remainSessions = append(remainSessions, session)
+		}
+
+		// sessions are sorted from the oldest, so the oldest ones are kicked out first
+		if req.MaxActive > 0 && len(remainSessions) >= req.MaxActive {
+			for _, session := range remainSessions[:len(remainSessions)-req.MaxActive+1] {
+				err = r.outport.RevokeSession(ctx, session.ID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		sessionObj := entity.NewSessionData(req)
+		err = r.outport.CreateSession(ctx, sessionObj)
+		if err != nil {
+			return err
+		}
+
+		refreshTokenObj := entity.NewRefreshTokenData(entity.CreateRefreshTokenData{
+			MemberId:  req.MemberId,
+			DeviceId:  req.DeviceId,
+			FamilyId:  sessionObj.ID,
+			ExpiredAt: req.ExpiredAt,
+		})
+		err = r.outport.CreateRefreshToken(ctx, refreshTokenObj)
+		if err != nil {
+			return err
+		}
+
+		response = &entity.CreateSessionRes{
+			Session:      sessionObj,
+			RefreshToken: refreshTokenObj,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
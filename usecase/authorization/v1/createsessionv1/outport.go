@@ -0,0 +1,12 @@
+package createsessionv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.SessionRepo
+	apibaseappgateway.RefreshTokenRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,57 @@
+package forgotpasswordv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+)
+
+type apibaseappforgotpasswordInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappforgotpasswordInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute does not tell whether the username exist, the caller always get a success response
+func (r *apibaseappforgotpasswordInteractor) Execute(ctx context.Context, req entity.ForgotPasswordReq) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		member, err := r.outport.FindOneMemberDataByUsername(ctx, req.Username)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			return nil
+		}
+
+		channel, to, err := member.NotificationAddress(req.Channel)
+		if err != nil {
+			if err == entity.NotificationAddressNotFound {
+				log.Error(ctx, err.Error())
+				return nil
+			}
+			return err
+		}
+
+		// only the latest token is valid
+		err = r.outport.DeletePasswordResetByMemberId(ctx, member.ID)
+		if err != nil {
+			return err
+		}
+
+		passwordReset, token, err := entity.NewPasswordResetData(member.ID, channel, req.ExpiredAt)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.CreatePasswordReset(ctx, *passwordReset)
+		if err != nil {
+			return err
+		}
+
+		return r.outport.SendNotification(ctx, passwordReset.ToNotificationData(to, req.ResetUrl, token))
+	})
+}
@@ -0,0 +1,14 @@
+package forgotpasswordv1
+
+import (
+	"backend_base_app/domain/service"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	service.NotificationService
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.PasswordResetRepo
+	dbhelpers.WithoutTransactionDB
+}
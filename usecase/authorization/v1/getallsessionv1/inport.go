@@ -0,0 +1,10 @@
+package getallsessionv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, memberId string) ([]entity.SessionData, error)
+}
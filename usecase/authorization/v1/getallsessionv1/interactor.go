@@ -0,0 +1,35 @@
+package getallsessionv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappsessiongetallInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappsessiongetallInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappsessiongetallInteractor) Execute(ctx context.Context, memberId string) ([]entity.SessionData, error) {
+	var response = []entity.SessionData{}
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, err := r.outport.FindAllActiveSessionByMemberId(ctx, memberId)
+		if err != nil {
+			return err
+		}
+
+		for _, session := range res {
+			response = append(response, *session)
+		}
+
+		return nil
+	})
+	return response, err
+}
@@ -0,0 +1,9 @@
+package getmemberpermissionv1
+
+import (
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, roles []string) ([]string, error)
+}
@@ -0,0 +1,41 @@
+package getmemberpermissionv1
+
+import (
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappmemberpermissionInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberpermissionInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmemberpermissionInteractor) Execute(ctx context.Context, roles []string) ([]string, error) {
+	var response = []string{}
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, err := r.outport.FindAllRoleByIds(ctx, roles)
+		if err != nil {
+			return err
+		}
+
+		registered := map[string]bool{}
+		for _, role := range res {
+			for _, permission := range role.Permissions {
+				if registered[permission] {
+					continue
+				}
+				registered[permission] = true
+				response = append(response, permission)
+			}
+		}
+
+		return nil
+	})
+	return response, err
+}
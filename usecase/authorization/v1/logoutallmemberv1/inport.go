@@ -0,0 +1,10 @@
+package logoutallmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.LogoutAllReq) error
+}
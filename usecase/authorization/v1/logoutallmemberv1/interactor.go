@@ -0,0 +1,29 @@
+package logoutallmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapplogoutallInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapplogoutallInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapplogoutallInteractor) Execute(ctx context.Context, req entity.LogoutAllReq) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		err := r.outport.RevokeToken(ctx, entity.NewRevokedMemberData(req.MemberId, req.ExpiredAt))
+		if err != nil {
+			return err
+		}
+
+		return r.outport.RevokeSessionByMemberId(ctx, req.MemberId)
+	})
+}
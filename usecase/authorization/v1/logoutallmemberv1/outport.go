@@ -0,0 +1,12 @@
+package logoutallmemberv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.RevokedTokenRepo
+	apibaseappgateway.SessionRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,33 @@
+package logoutmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapplogoutInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapplogoutInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapplogoutInteractor) Execute(ctx context.Context, req entity.LogoutReq) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		err := r.outport.RevokeToken(ctx, entity.NewRevokedTokenData(req.MemberId, req.TokenId, req.ExpiredAt))
+		if err != nil {
+			return err
+		}
+
+		if req.FamilyId == "" {
+			return nil
+		}
+
+		return r.outport.RevokeSession(ctx, req.FamilyId)
+	})
+}
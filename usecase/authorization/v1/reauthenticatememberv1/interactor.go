@@ -0,0 +1,135 @@
+package reauthenticatememberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+type apibaseappreauthenticatememberInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappreauthenticatememberInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappreauthenticatememberInteractor) Execute(ctx context.Context, req entity.ReauthenticateReq) (*entity.ReauthenticateRes, error) {
+	response := &entity.ReauthenticateRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+		session, err := r.outport.FindOneSessionById(ctx, req.SessionId)
+		if err != nil {
+			return err
+		}
+		if session.MemberId != req.MemberId || !session.IsActive() {
+			return entity.SessionHasBeenRevoked
+		}
+
+		member, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		// a reauthentication is a login, it share the counters so it can not be used to guess the password
+		if req.LoginProtection.Enabled {
+			attempts, err := r.outport.FindAllLoginAttemptByIds(ctx, entity.LoginAttemptIds(member.Username, req.IpAddress))
+			if err != nil {
+				return err
+			}
+			err = entity.CheckLoginAttempts(attempts, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(req.Password) != 0 {
+			_, err = r.outport.VerifyMemberCredential(ctx, entity.MemberReqAuth{Username: member.Username, Password: req.Password})
+			if err != nil {
+				if req.LoginProtection.Enabled && errors.Is(err, apibaseappgateway.InvalidUsernameOrPassword) {
+					r.recordFailedLoginAttempt(ctx, member.Username, req)
+				}
+				return err
+			}
+		}
+
+		if len(strings.TrimSpace(req.Code)) != 0 {
+			err = r.verifyTwoFactorCode(ctx, member.Username, req)
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.LoginProtection.Enabled {
+			err = r.outport.DeleteLoginAttempt(ctx, entity.LoginAttemptId(entity.LoginAttemptKindUsername, member.Username))
+			if err != nil {
+				log.Error(ctx, err.Error())
+			}
+		}
+
+		session.AuthTime = time.Now()
+		session.Amr = req.AuthMethods()
+
+		err = r.outport.UpdateSessionAuthentication(ctx, session.ID, session.AuthTime, session.Amr)
+		if err != nil {
+			return err
+		}
+
+		response.Member = *member
+		response.Session = *session
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// verifyTwoFactorCode accept a totp or a recovery code, each code is used once
+func (r *apibaseappreauthenticatememberInteractor) verifyTwoFactorCode(ctx context.Context, username string, req entity.ReauthenticateReq) error {
+	twoFactor, err := r.outport.FindOneTwoFactorByMemberId(ctx, req.MemberId)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.IsEnabled {
+		return entity.TwoFactorNotEnrolled
+	}
+
+	codeUse, ok := twoFactor.VerifyCode(req.Code, req.Config, time.Now())
+	if !ok {
+		if req.LoginProtection.Enabled {
+			r.recordFailedLoginAttempt(ctx, username, req)
+		}
+		return entity.TwoFactorCodeInvalid
+	}
+
+	return r.outport.UseTwoFactorCode(ctx, codeUse)
+}
+
+// recordFailedLoginAttempt only log its error, so the member still get the invalid password or code error
+func (r *apibaseappreauthenticatememberInteractor) recordFailedLoginAttempt(ctx context.Context, username string, req entity.ReauthenticateReq) {
+	now := time.Now()
+	for kind, key := range entity.LoginAttemptKeys(username, req.IpAddress) {
+		attempt, err := r.outport.IncrementLoginAttempt(ctx, entity.NewLoginAttemptData(kind, key, now.Add(req.LoginProtection.Window)))
+		if err != nil {
+			log.Error(ctx, err.Error())
+			continue
+		}
+
+		attempt.ApplyFailure(req.LoginProtection, now)
+
+		err = r.outport.UpdateLoginAttempt(ctx, *attempt)
+		if err != nil {
+			log.Error(ctx, err.Error())
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package refreshauthmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.RefreshAuthReq) (*entity.RefreshAuthRes, error)
+}
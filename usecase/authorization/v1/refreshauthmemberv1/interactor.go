@@ -0,0 +1,99 @@
+package refreshauthmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+)
+
+type apibaseapprefreshauthInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapprefreshauthInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapprefreshauthInteractor) Execute(ctx context.Context, req entity.RefreshAuthReq) (*entity.RefreshAuthRes, error) {
+	response := &entity.RefreshAuthRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+		if req.Token.TokenId == "" || req.Token.FamilyId == "" {
+			return entity.RefreshTokenInvalid
+		}
+
+		refreshTokenObj, err := r.outport.UseRefreshToken(ctx, req.Token.TokenId)
+		if err != nil {
+			return err
+		}
+
+		if refreshTokenObj.MemberId != req.Token.Id || refreshTokenObj.FamilyId != req.Token.FamilyId {
+			return entity.RefreshTokenInvalid
+		}
+
+		if refreshTokenObj.IsRevoked {
+			return entity.RefreshTokenRevoked
+		}
+
+		// a used token presented again means it has leaked, so nobody in the family can be trusted anymore
+		if refreshTokenObj.IsUsed {
+			log.Error(ctx, "refresh token %s reused, revoking family %s", refreshTokenObj.ID, refreshTokenObj.FamilyId)
+			err = r.outport.RevokeSession(ctx, refreshTokenObj.FamilyId)
+			if err != nil {
+				return err
+			}
+			return entity.RefreshTokenReuseDetected
+		}
+
+		if refreshTokenObj.IsExpired() {
+			return entity.RefreshTokenExpired
+		}
+
+		session, err := r.outport.FindOneSessionById(ctx, refreshTokenObj.FamilyId)
+		if err != nil {
+			return err
+		}
+		if !session.IsActive() {
+			return entity.SessionHasBeenRevoked
+		}
+
+		member, err := r.outport.FindOneMemberDataById(ctx, refreshTokenObj.MemberId)
+		if err != nil {
+			return err
+		}
+
+		newRefreshTokenObj := entity.NewRefreshTokenData(entity.CreateRefreshTokenData{
+			MemberId:  refreshTokenObj.MemberId,
+			DeviceId:  refreshTokenObj.DeviceId,
+			FamilyId:  refreshTokenObj.FamilyId,
+			ParentId:  refreshTokenObj.ID,
+			ExpiredAt: req.ExpiredAt,
+		})
+
+		err = r.outport.CreateRefreshToken(ctx, newRefreshTokenObj)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.ExtendSession(ctx, session.ID, req.ExpiredAt)
+		if err != nil {
+			return err
+		}
+
+		response = &entity.RefreshAuthRes{
+			Member:       *member,
+			RefreshToken: newRefreshTokenObj,
+			Session:      *session,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
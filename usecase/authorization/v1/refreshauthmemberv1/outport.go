@@ -0,0 +1,13 @@
+package refreshauthmemberv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.RefreshTokenRepo
+	apibaseappgateway.SessionRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,75 @@
+package resetpasswordv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/util"
+	"context"
+)
+
+type apibaseappresetpasswordInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappresetpasswordInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute set the new password and logout the member from every session
+func (r *apibaseappresetpasswordInteractor) Execute(ctx context.Context, req entity.ResetPasswordReq) (*entity.MemberDataShown, error) {
+	res := &entity.MemberDataShown{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		// the policy is checked before the token is used, so the member can try another password
+		passwordReset, err := r.outport.FindOnePasswordReset(ctx, util.HashToken(req.Token))
+		if err != nil {
+			return err
+		}
+
+		member, err := r.outport.FindOneMemberDataById(ctx, passwordReset.MemberId)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.CheckPasswordPolicy(ctx, entity.CheckPasswordPolicyReq{
+			MemberId:   member.ID,
+			MemberType: member.MemberType,
+			Password:   req.Password,
+		})
+		if err != nil {
+			return err
+		}
+
+		passwordReset, err = r.outport.UsePasswordReset(ctx, util.HashToken(req.Token))
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.UpdateMemberPassword(ctx, passwordReset.MemberId, req.Password)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.RevokeToken(ctx, entity.NewRevokedMemberData(passwordReset.MemberId, req.RevokeExpiredAt))
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.RevokeSessionByMemberId(ctx, passwordReset.MemberId)
+		if err != nil {
+			return err
+		}
+
+		res = member
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,16 @@
+package resetpasswordv1
+
+import (
+	"backend_base_app/domain/service"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	service.PasswordPolicyService
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.PasswordResetRepo
+	apibaseappgateway.RevokedTokenRepo
+	apibaseappgateway.SessionRepo
+	dbhelpers.WithoutTransactionDB
+}
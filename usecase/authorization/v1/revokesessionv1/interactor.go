@@ -0,0 +1,34 @@
+package revokesessionv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappsessionrevokeInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappsessionrevokeInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappsessionrevokeInteractor) Execute(ctx context.Context, req entity.RevokeSessionReq) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		session, err := r.outport.FindOneSessionById(ctx, req.SessionId)
+		if err != nil {
+			return err
+		}
+
+		// never reveal the sessions of other member
+		if session.MemberId != req.MemberId {
+			return entity.SessionNotFound
+		}
+
+		return r.outport.RevokeSession(ctx, session.ID)
+	})
+}
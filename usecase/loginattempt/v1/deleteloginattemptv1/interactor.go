@@ -0,0 +1,33 @@
+package deleteloginattemptv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapploginattemptdeleteInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapploginattemptdeleteInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute clear the counter, so the username or ip can login again right away
+func (r *apibaseapploginattemptdeleteInteractor) Execute(ctx context.Context, id string) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, err := r.outport.FindAllLoginAttemptByIds(ctx, []string{id})
+		if err != nil {
+			return err
+		}
+		if len(res) == 0 {
+			return entity.LoginAttemptNotFound.Var(id)
+		}
+
+		return r.outport.DeleteLoginAttempt(ctx, id)
+	})
+}
@@ -0,0 +1,10 @@
+package getallloginattemptv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.BaseReqFind) ([]entity.LoginAttemptData, int64, error)
+}
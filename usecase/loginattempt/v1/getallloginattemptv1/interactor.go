@@ -0,0 +1,38 @@
+package getallloginattemptv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapploginattemptgetallInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapploginattemptgetallInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapploginattemptgetallInteractor) Execute(ctx context.Context, req entity.BaseReqFind) ([]entity.LoginAttemptData, int64, error) {
+	var response = []entity.LoginAttemptData{}
+	var totalRecords = int64(-1)
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, count, err := r.outport.FindAllLoginAttempt(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, attempt := range res {
+			response = append(response, *attempt)
+		}
+
+		totalRecords = count
+
+		return nil
+	})
+	return response, totalRecords, err
+}
@@ -0,0 +1,124 @@
+package assignmemberrolev1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappmemberassignroleInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberassignroleInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmemberassignroleInteractor) Execute(ctx context.Context, req entity.AssignMemberRoleReq) (*entity.AssignMemberRoleRes, error) {
+	res := &entity.AssignMemberRoleRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		previous, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		roles := []string{}
+		if req.Roles != nil {
+			roles = req.Roles
+		}
+
+		added, removed := changedRoles(previous.Roles, roles)
+		changed := append(append([]string{}, added...), removed...)
+
+		existingRoles, err := r.outport.FindAllRoleByIds(ctx, changed)
+		if err != nil {
+			return err
+		}
+
+		registered := map[string]*entity.RoleData{}
+		for _, role := range existingRoles {
+			registered[role.ID] = role
+		}
+		for _, role := range added {
+			if registered[role] == nil {
+				return entity.RoleNotFound.Var(role)
+			}
+		}
+
+		// a role is only given or taken by a member holding all its permissions,
+		// a removed role which does not exist anymore grant nothing
+		for _, role := range changed {
+			if role == entity.RoleSuperadmin {
+				err = entity.ValidateGrantedPermissions(req.GrantedPermissions, []string{entity.PermissionAll})
+				if err != nil {
+					return err
+				}
+			}
+			if registered[role] == nil {
+				continue
+			}
+			err = entity.ValidateGrantedPermissions(req.GrantedPermissions, registered[role].Permissions)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, role := range removed {
+			if role != entity.RoleSuperadmin || previous.IsSuspend {
+				continue
+			}
+			count, err := r.outport.CountMemberByRole(ctx, entity.RoleSuperadmin)
+			if err != nil {
+				return err
+			}
+			if count <= 1 {
+				return entity.LastSuperadminRole
+			}
+		}
+
+		member, err := r.outport.UpdateMemberRoles(ctx, req.MemberId, roles)
+		if err != nil {
+			return err
+		}
+
+		res.Member = *member
+		res.PreviousRoles = previous.Roles
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// changedRoles return the roles which are in next but not in previous, and the ones which are in previous but not in next
+func changedRoles(previous []string, next []string) ([]string, []string) {
+	previousSet := map[string]bool{}
+	for _, role := range previous {
+		previousSet[role] = true
+	}
+	nextSet := map[string]bool{}
+	for _, role := range next {
+		nextSet[role] = true
+	}
+
+	added := []string{}
+	for role := range nextSet {
+		if !previousSet[role] {
+			added = append(added, role)
+		}
+	}
+	removed := []string{}
+	for role := range previousSet {
+		if !nextSet[role] {
+			removed = append(removed, role)
+		}
+	}
+	return added, removed
+}
@@ -35,8 +35,19 @@ func (r *apibaseappmembercreateInteractor) Execute(ctx context.Context, req enti
 			return err
 		}
 
+		err = r.outport.CheckPasswordPolicy(ctx, entity.CheckPasswordPolicyReq{
+			MemberType: memberDataObj.MemberType,
+			Password:   req.Password,
+		})
+		if err != nil {
+			return err
+		}
+
 		//encrypt password
-		password := r.outport.EncryptPassword(ctx, req.Password)
+		password, err := r.outport.EncryptPassword(ctx, req.Password)
+		if err != nil {
+			return err
+		}
 		memberDataObj.Password = password
 
 		log.Info(ctx, util.StructToJson(memberDataObj))
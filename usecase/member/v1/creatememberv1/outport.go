@@ -9,6 +9,7 @@ import (
 type Outport interface {
 	service.GenerateIDService
 	service.EncryptPasswordService
+	service.PasswordPolicyService
 	apibaseappgateway.CreateMemberDataRepo
 	dbhelpers.WithoutTransactionDB
 }
@@ -0,0 +1,99 @@
+package deletememberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+	"time"
+)
+
+type apibaseappmemberdeleteInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberdeleteInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmemberdeleteInteractor) Execute(ctx context.Context, req entity.DeleteMemberReq) (*entity.DeleteMemberRes, error) {
+	res := &entity.DeleteMemberRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		if req.StepUpMaxAge > 0 {
+			err := entity.CheckRecentAuthentication(req.AuthTime, req.StepUpMaxAge, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		previous, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		if req.DeletedBy != req.MemberId {
+			err = r.validateGrantedRoles(ctx, req.GrantedPermissions, *previous)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = r.checkLastSuperadmin(ctx, *previous, "deleted")
+		if err != nil {
+			return err
+		}
+
+		member, err := r.outport.SoftDeleteMemberData(ctx, req.MemberId, req.DeletedBy)
+		if err != nil {
+			return err
+		}
+
+		// a deleted member is logged out everywhere, a restored member login again
+		err = r.outport.RevokeToken(ctx, entity.NewRevokedMemberData(req.MemberId, req.ExpiredAt))
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.RevokeSessionByMemberId(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		res.Member = *member
+		res.Previous = *previous
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// validateGrantedRoles refuse to act on a member holding a permission the member making the change does not hold
+func (r *apibaseappmemberdeleteInteractor) validateGrantedRoles(ctx context.Context, grantedPermissions []string, member entity.MemberDataShown) error {
+	roles, err := r.outport.FindAllRoleByIds(ctx, member.GetRoles())
+	if err != nil {
+		return err
+	}
+	return entity.ValidateGrantedRoles(grantedPermissions, roles)
+}
+
+// checkLastSuperadmin refuse to remove the last superadmin, the roles could not be managed anymore
+func (r *apibaseappmemberdeleteInteractor) checkLastSuperadmin(ctx context.Context, member entity.MemberDataShown, action string) error {
+	if !member.HasRole(entity.RoleSuperadmin) || member.IsSuspend {
+		return nil
+	}
+	count, err := r.outport.CountMemberByRole(ctx, entity.RoleSuperadmin)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return entity.LastSuperadmin.Var(action)
+	}
+	return nil
+}
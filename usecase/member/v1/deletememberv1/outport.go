@@ -0,0 +1,14 @@
+package deletememberv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.RoleRepo
+	apibaseappgateway.RevokedTokenRepo
+	apibaseappgateway.SessionRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,11 @@
+package impersonatememberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+// Inport return the member to impersonate, the token is signed by the controller
+type Inport interface {
+	Execute(ctx context.Context, req entity.ImpersonateMemberReq) (*entity.MemberDataShown, error)
+}
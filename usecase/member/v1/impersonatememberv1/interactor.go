@@ -0,0 +1,43 @@
+package impersonatememberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappmemberimpersonateInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberimpersonateInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmemberimpersonateInteractor) Execute(ctx context.Context, req entity.ImpersonateMemberReq) (*entity.MemberDataShown, error) {
+	res := &entity.MemberDataShown{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		member, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		err = member.CheckImpersonable()
+		if err != nil {
+			return err
+		}
+
+		res = member
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,174 @@
+package importmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"context"
+	"errors"
+)
+
+type apibaseappmemberimportInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberimportInteractor{
+		outport: outputPort,
+	}
+}
+
+// importCandidate is a valid row waiting for the uniqueness check and the bulk write,
+// index is its position in the results of the batch
+type importCandidate struct {
+	index  int
+	member entity.MemberData
+}
+
+// Execute validate the rows like the member creation and write them by batch, a row never fail the others.
+// A dry run stop before hashing the passwords and writing
+func (r *apibaseappmemberimportInteractor) Execute(ctx context.Context, req entity.ImportMemberReq) (*entity.ImportMemberRes, error) {
+	res := &entity.ImportMemberRes{DryRun: req.DryRun, Total: len(req.Rows), Rows: []entity.MemberImportRowResult{}}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(req.Rows)
+	}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		// seen hold the unique keys of the previous rows, a duplicate in the file is skipped like a taken one
+		seen := map[string]bool{}
+
+		for start := 0; start < len(req.Rows); start += batchSize {
+			end := start + batchSize
+			if end > len(req.Rows) {
+				end = len(req.Rows)
+			}
+
+			results, err := r.importBatch(ctx, req.Rows[start:end], req.DryRun, seen)
+			if err != nil {
+				return err
+			}
+			for _, result := range results {
+				res.Add(result)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *apibaseappmemberimportInteractor) importBatch(ctx context.Context, rows []entity.MemberImportRow, dryRun bool, seen map[string]bool) ([]entity.MemberImportRowResult, error) {
+	results := make([]entity.MemberImportRowResult, len(rows))
+	candidates := []importCandidate{}
+
+	for i, row := range rows {
+		results[i] = entity.MemberImportRowResult{Line: row.Line, Username: row.Member.Username, Status: entity.MemberImportFailed}
+
+		member, err := entity.NewMemberData(row.Member)
+		if err != nil {
+			results[i].Reason = err.Error()
+			continue
+		}
+
+		// the time based id of NewMemberData is the same for every member created in the same second
+		member.ID, _ = entity.NewMemberDataID(util.GenerateUuidWithoutDash())
+
+		err = r.outport.CheckPasswordPolicy(ctx, entity.CheckPasswordPolicyReq{
+			MemberType: member.MemberType,
+			Password:   row.Member.Password,
+		})
+		if err != nil {
+			results[i].Reason = err.Error()
+			continue
+		}
+
+		duplicated := false
+		for _, key := range member.UniqueKeys() {
+			duplicated = duplicated || seen[key]
+			seen[key] = true
+		}
+		if duplicated {
+			results[i].Status, results[i].Reason = entity.MemberImportSkipped, entity.MemberImportDuplicated.Error()
+			continue
+		}
+
+		candidates = append(candidates, importCandidate{index: i, member: *member})
+	}
+
+	members := make([]entity.MemberData, 0, len(candidates))
+	for _, candidate := range candidates {
+		members = append(members, candidate.member)
+	}
+	taken, err := r.outport.FindTakenMemberKeys(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+
+	writable := []importCandidate{}
+	for _, candidate := range candidates {
+		result := &results[candidate.index]
+
+		isTaken := false
+		for _, key := range candidate.member.UniqueKeys() {
+			isTaken = isTaken || taken[key]
+		}
+		if isTaken {
+			result.Status, result.Reason = entity.MemberImportSkipped, apibaseappgateway.DataRegistraionHasTaken.Error()
+			continue
+		}
+
+		if dryRun {
+			result.Status = entity.MemberImportCreated
+			continue
+		}
+
+		password, err := r.outport.EncryptPassword(ctx, candidate.member.Password)
+		if err != nil {
+			result.Reason = err.Error()
+			continue
+		}
+		candidate.member.Password = password
+
+		writable = append(writable, candidate)
+	}
+
+	if len(writable) == 0 {
+		return results, nil
+	}
+
+	members = make([]entity.MemberData, 0, len(writable))
+	for _, candidate := range writable {
+		members = append(members, candidate.member)
+	}
+	rowErrs, err := r.outport.CreateManyMemberData(ctx, members)
+	if rowErrs == nil {
+		return nil, err
+	}
+	if err != nil {
+		// the members are written, only their password history is missing
+		log.Error(ctx, err.Error())
+	}
+
+	for i, candidate := range writable {
+		result := &results[candidate.index]
+		switch {
+		case rowErrs[i] == nil:
+			result.Status, result.MemberId = entity.MemberImportCreated, candidate.member.ID.String()
+		case errors.Is(rowErrs[i], apibaseappgateway.DataRegistraionHasTaken):
+			result.Status, result.Reason = entity.MemberImportSkipped, rowErrs[i].Error()
+		default:
+			result.Reason = rowErrs[i].Error()
+		}
+	}
+
+	return results, nil
+}
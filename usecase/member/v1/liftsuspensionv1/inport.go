@@ -0,0 +1,11 @@
+package liftsuspensionv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+// Inport lift every suspension whose suspended_until has passed and return the lifted suspensions
+type Inport interface {
+	Execute(ctx context.Context) ([]entity.SuspendMemberRes, error)
+}
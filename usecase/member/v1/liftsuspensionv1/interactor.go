@@ -0,0 +1,54 @@
+package liftsuspensionv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"errors"
+	"time"
+)
+
+type apibaseappmemberliftsuspensionInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberliftsuspensionInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmemberliftsuspensionInteractor) Execute(ctx context.Context) ([]entity.SuspendMemberRes, error) {
+	res := []entity.SuspendMemberRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		now := time.Now().Local().UTC()
+		members, err := r.outport.FindAllMemberDataSuspensionEnded(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		for _, previous := range members {
+			member, err := r.outport.UnsuspendMemberData(ctx, previous.ID, &now)
+			if err != nil {
+				// MemberNotSuspended means an admin lifted or extended it since it was found,
+				// any other failure must not keep the next members suspended
+				if !errors.Is(err, entity.MemberNotSuspended) {
+					log.Error(ctx, err.Error())
+				}
+				continue
+			}
+
+			res = append(res, entity.SuspendMemberRes{Member: *member, Previous: *previous})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package purgememberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+)
+
+type apibaseappmemberpurgeInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberpurgeInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute return the purged member, only a soft deleted member can be purged
+func (r *apibaseappmemberpurgeInteractor) Execute(ctx context.Context, req entity.PurgeMemberReq) (*entity.MemberDataShown, error) {
+	res := &entity.MemberDataShown{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		member, err := r.outport.FindOneMemberDataByIdWithDeleted(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+		if !member.IsDeleted() {
+			return entity.MemberNotDeleted
+		}
+
+		err = r.validateGrantedRoles(ctx, req.GrantedPermissions, *member)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.PurgeMemberData(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		// a leftover photo object does not fail the purge
+		for _, key := range member.MemberPhotoKeys {
+			if err := r.outport.DeleteObject(ctx, key); err != nil {
+				log.Error(ctx, err.Error())
+			}
+		}
+
+		res = member
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// validateGrantedRoles refuse to act on a member holding a permission the member making the change does not hold
+func (r *apibaseappmemberpurgeInteractor) validateGrantedRoles(ctx context.Context, grantedPermissions []string, member entity.MemberDataShown) error {
+	roles, err := r.outport.FindAllRoleByIds(ctx, member.GetRoles())
+	if err != nil {
+		return err
+	}
+	return entity.ValidateGrantedRoles(grantedPermissions, roles)
+}
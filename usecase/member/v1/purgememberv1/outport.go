@@ -0,0 +1,14 @@
+package purgememberv1
+
+import (
+	"backend_base_app/domain/service"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.RoleRepo
+	service.ObjectStorageService
+	dbhelpers.WithoutTransactionDB
+}
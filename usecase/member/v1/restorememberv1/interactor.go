@@ -0,0 +1,58 @@
+package restorememberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappmemberrestoreInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberrestoreInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmemberrestoreInteractor) Execute(ctx context.Context, req entity.RestoreMemberReq) (*entity.DeleteMemberRes, error) {
+	res := &entity.DeleteMemberRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		previous, err := r.outport.FindOneMemberDataByIdWithDeleted(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		err = r.validateGrantedRoles(ctx, req.GrantedPermissions, *previous)
+		if err != nil {
+			return err
+		}
+
+		member, err := r.outport.RestoreMemberData(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		res.Member = *member
+		res.Previous = *previous
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// validateGrantedRoles refuse to act on a member holding a permission the member making the change does not hold
+func (r *apibaseappmemberrestoreInteractor) validateGrantedRoles(ctx context.Context, grantedPermissions []string, member entity.MemberDataShown) error {
+	roles, err := r.outport.FindAllRoleByIds(ctx, member.GetRoles())
+	if err != nil {
+		return err
+	}
+	return entity.ValidateGrantedRoles(grantedPermissions, roles)
+}
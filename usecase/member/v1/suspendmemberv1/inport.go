@@ -0,0 +1,10 @@
+package suspendmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.SuspendMemberReq) (*entity.SuspendMemberRes, error)
+}
@@ -0,0 +1,89 @@
+package suspendmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappmembersuspendInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmembersuspendInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmembersuspendInteractor) Execute(ctx context.Context, req entity.SuspendMemberReq) (*entity.SuspendMemberRes, error) {
+	res := &entity.SuspendMemberRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		previous, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		err = r.validateGrantedRoles(ctx, req.GrantedPermissions, *previous)
+		if err != nil {
+			return err
+		}
+
+		err = r.checkLastSuperadmin(ctx, *previous)
+		if err != nil {
+			return err
+		}
+
+		member, err := r.outport.SuspendMemberData(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		// the member must not keep using the tokens issued before the suspension
+		err = r.outport.RevokeToken(ctx, entity.NewRevokedMemberData(req.MemberId, req.ExpiredAt))
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.RevokeSessionByMemberId(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		res.Member = *member
+		res.Previous = *previous
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// validateGrantedRoles refuse to act on a member holding a permission the member making the change does not hold
+func (r *apibaseappmembersuspendInteractor) validateGrantedRoles(ctx context.Context, grantedPermissions []string, member entity.MemberDataShown) error {
+	roles, err := r.outport.FindAllRoleByIds(ctx, member.GetRoles())
+	if err != nil {
+		return err
+	}
+	return entity.ValidateGrantedRoles(grantedPermissions, roles)
+}
+
+// checkLastSuperadmin refuse to suspend the last superadmin, the roles could not be managed anymore
+func (r *apibaseappmembersuspendInteractor) checkLastSuperadmin(ctx context.Context, member entity.MemberDataShown) error {
+	if !member.HasRole(entity.RoleSuperadmin) || member.IsSuspend {
+		return nil
+	}
+	count, err := r.outport.CountMemberByRole(ctx, entity.RoleSuperadmin)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return entity.LastSuperadmin.Var("suspended")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package unsuspendmemberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappmemberunsuspendInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberunsuspendInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmemberunsuspendInteractor) Execute(ctx context.Context, req entity.UnsuspendMemberReq) (*entity.SuspendMemberRes, error) {
+	res := &entity.SuspendMemberRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		previous, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		member, err := r.outport.UnsuspendMemberData(ctx, req.MemberId, nil)
+		if err != nil {
+			return err
+		}
+
+		res.Member = *member
+		res.Previous = *previous
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
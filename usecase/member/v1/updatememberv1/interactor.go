@@ -0,0 +1,86 @@
+package updatememberv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+	"time"
+)
+
+type apibaseappmemberupdateInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberupdateInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappmemberupdateInteractor) Execute(ctx context.Context, req entity.UpdateMemberReq) (*entity.UpdateMemberRes, error) {
+	res := &entity.UpdateMemberRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		previous, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		// the email and phone number reset the password, a less privileged member could take the account over
+		if req.UpdatedBy != req.MemberId {
+			err = r.validateGrantedRoles(ctx, req.GrantedPermissions, *previous)
+			if err != nil {
+				return err
+			}
+		}
+
+		changes, err := req.Changes(*previous)
+		if err != nil {
+			return err
+		}
+
+		res.Previous = *previous
+		if len(changes) == 0 {
+			res.Member = *previous
+			return nil
+		}
+
+		_, emailChanged := changes["email"]
+		_, phoneNumberChanged := changes["phone_number"]
+		if (emailChanged || phoneNumberChanged) && req.StepUpMaxAge > 0 {
+			err = entity.CheckRecentAuthentication(req.AuthTime, req.StepUpMaxAge, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		err = r.outport.CheckMemberDataTaken(ctx, req.MemberId, entity.UniqueMemberFields(changes))
+		if err != nil {
+			return err
+		}
+
+		member, err := r.outport.UpdateMemberDataFields(ctx, req.MemberId, changes)
+		if err != nil {
+			return err
+		}
+
+		res.Member = *member
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// validateGrantedRoles refuse to update a member holding a permission the member making the change does not hold
+func (r *apibaseappmemberupdateInteractor) validateGrantedRoles(ctx context.Context, grantedPermissions []string, member entity.MemberDataShown) error {
+	roles, err := r.outport.FindAllRoleByIds(ctx, member.GetRoles())
+	if err != nil {
+		return err
+	}
+	return entity.ValidateGrantedRoles(grantedPermissions, roles)
+}
@@ -0,0 +1,10 @@
+package uploadmemberphotov1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.UploadMemberPhotoReq) (*entity.UploadMemberPhotoRes, error)
+}
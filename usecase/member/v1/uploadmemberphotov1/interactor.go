@@ -0,0 +1,101 @@
+package uploadmemberphotov1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"context"
+	"strconv"
+)
+
+type apibaseappmemberphotouploadInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappmemberphotouploadInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute store the photo and its thumbnails then write their url into the member,
+// the objects of the replaced photo are removed afterwards
+func (r *apibaseappmemberphotouploadInteractor) Execute(ctx context.Context, req entity.UploadMemberPhotoReq) (*entity.UploadMemberPhotoRes, error) {
+	res := &entity.UploadMemberPhotoRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		previous, err := r.outport.FindOneMemberDataById(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		// decoding the image first also reject a file which only look like an image
+		thumbnails, err := r.outport.CreateThumbnails(ctx, req.Data, req.Config.ThumbnailSizes)
+		if err != nil {
+			return err
+		}
+
+		photoId := util.GenerateUuidWithoutDash()
+		keys := []string{}
+
+		photoKey := req.ObjectKey(photoId, 0, req.ContentType)
+		photoUrl, err := r.outport.PutObject(ctx, entity.StorageObject{
+			Key:         photoKey,
+			ContentType: req.ContentType,
+			Data:        req.Data,
+		})
+		if err != nil {
+			return err
+		}
+		keys = append(keys, photoKey)
+
+		thumbnailUrls := map[string]string{}
+		for _, thumbnail := range thumbnails {
+			thumbnailKey := req.ObjectKey(photoId, thumbnail.Size, thumbnail.ContentType)
+			thumbnailUrl, err := r.outport.PutObject(ctx, entity.StorageObject{
+				Key:         thumbnailKey,
+				ContentType: thumbnail.ContentType,
+				Data:        thumbnail.Data,
+			})
+			if err != nil {
+				r.deleteObjects(ctx, keys)
+				return err
+			}
+			keys = append(keys, thumbnailKey)
+			thumbnailUrls[strconv.Itoa(thumbnail.Size)] = thumbnailUrl
+		}
+
+		member, err := r.outport.UpdateMemberDataFields(ctx, req.MemberId, map[string]interface{}{
+			"photo_member":            photoUrl,
+			"photo_member_thumbnails": thumbnailUrls,
+			"photo_member_keys":       keys,
+		})
+		if err != nil {
+			r.deleteObjects(ctx, keys)
+			return err
+		}
+
+		r.deleteObjects(ctx, previous.MemberPhotoKeys)
+
+		res.Previous = *previous
+		res.Member = *member
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// deleteObjects only log the failure, a leftover object does not fail the upload
+func (r *apibaseappmemberphotouploadInteractor) deleteObjects(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := r.outport.DeleteObject(ctx, key); err != nil {
+			log.Error(ctx, err.Error())
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package uploadmemberphotov1
+
+import (
+	"backend_base_app/domain/service"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.CreateMemberDataRepo
+	service.ObjectStorageService
+	service.ImageService
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,166 @@
+package callbackoidcv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"backend_base_app/shared/util"
+	"context"
+	"errors"
+	"time"
+)
+
+type apibaseappcallbackoidcInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappcallbackoidcInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappcallbackoidcInteractor) Execute(ctx context.Context, req entity.OidcCallbackReq) (*entity.OidcCallbackRes, error) {
+	response := &entity.OidcCallbackRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		// the state is consumed before anything else, a callback can not be replayed
+		state, err := r.outport.UseOidcState(ctx, util.HashToken(req.State))
+		if err != nil {
+			return err
+		}
+		if state.Provider != req.Provider {
+			return entity.OidcStateInvalid
+		}
+
+		identity, err := r.outport.OidcExchange(ctx, entity.OidcExchangeReq{
+			Provider:     req.Provider,
+			Code:         req.Code,
+			CodeVerifier: state.CodeVerifier,
+			Nonce:        state.Nonce,
+		})
+		if err != nil {
+			return entity.OidcProviderError.Var(err.Error())
+		}
+
+		member, err := r.findOrLinkMember(ctx, *identity, req.Config)
+		if err != nil {
+			return err
+		}
+
+		if member.IsSuspend {
+			return entity.NewMyError("Account is Suspended")
+		}
+
+		response.DeviceId = state.DeviceId
+		response.Platform = state.Platform
+
+		// the provider replace the password, not the second factor
+		twoFactor, err := r.outport.FindOneTwoFactorByMemberId(ctx, member.ID)
+		if err != nil && !errors.Is(err, entity.TwoFactorNotEnrolled) {
+			return err
+		}
+		if twoFactor != nil && twoFactor.IsEnabled {
+			response.Member = *member
+			response.TwoFactorRequired = true
+			return nil
+		}
+
+		res, err := r.outport.CompleteMemberLogin(ctx, *member, state.DeviceId, "")
+		if err != nil {
+			return err
+		}
+
+		response.Member = *res
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// findOrLinkMember return the member linked to the identity. An identity seen for the first time is
+// linked by its verified email, or create a member when the config allow it
+func (r *apibaseappcallbackoidcInteractor) findOrLinkMember(ctx context.Context, identity entity.OidcIdentity, config entity.OidcLoginConfig) (*entity.MemberDataShown, error) {
+	identityId := entity.MemberIdentityId(identity.Provider, identity.Subject)
+
+	memberIdentity, err := r.outport.FindOneMemberIdentityById(ctx, identityId)
+	if err != nil {
+		return nil, err
+	}
+	if memberIdentity != nil {
+		err = r.outport.TouchMemberIdentity(ctx, identityId)
+		if err != nil {
+			log.Error(ctx, err.Error())
+		}
+		return r.outport.FindOneMemberDataById(ctx, memberIdentity.MemberId)
+	}
+
+	// an unverified email could belong to anybody, it is never used to reach a member
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, entity.OidcEmailNotVerified
+	}
+
+	member, err := r.outport.FindOneMemberDataByEmail(ctx, identity.Email)
+	if err != nil && !errors.Is(err, entity.MemberNotFound) {
+		return nil, err
+	}
+
+	if member != nil {
+		// the member must have proven the email too, otherwise whoever registered it first get the account
+		if member.EmailVerifiedAt == nil {
+			return nil, entity.OidcMemberNotLinkable.Var(identity.Email)
+		}
+	} else {
+		if !config.AllowCreate {
+			return nil, entity.OidcMemberNotRegistered.Var(identity.Email)
+		}
+
+		member, err = r.createMember(ctx, identity, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = r.outport.CreateMemberIdentity(ctx, entity.NewMemberIdentityData(identity, member.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (r *apibaseappcallbackoidcInteractor) createMember(ctx context.Context, identity entity.OidcIdentity, config entity.OidcLoginConfig) (*entity.MemberDataShown, error) {
+	createMemberData, err := identity.ToCreateMemberData(config.MemberType)
+	if err != nil {
+		return nil, err
+	}
+
+	memberDataObj, err := entity.NewMemberData(*createMemberData)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := r.outport.EncryptPassword(ctx, createMemberData.Password)
+	if err != nil {
+		return nil, err
+	}
+	memberDataObj.Password = password
+
+	// the provider has verified the email
+	now := time.Now()
+	memberDataObj.EmailVerifiedAt = &now
+
+	err = r.outport.CreateMemberData(ctx, *memberDataObj)
+	if err != nil {
+		return nil, err
+	}
+
+	memberShown := memberDataObj.ToShown()
+
+	return &memberShown, nil
+}
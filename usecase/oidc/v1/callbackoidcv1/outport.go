@@ -0,0 +1,17 @@
+package callbackoidcv1
+
+import (
+	"backend_base_app/domain/service"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	service.EncryptPasswordService
+	service.IdentityProviderService
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.OidcStateRepo
+	apibaseappgateway.MemberIdentityRepo
+	apibaseappgateway.TwoFactorRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,54 @@
+package startoidcv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseappstartoidcInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappstartoidcInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappstartoidcInteractor) Execute(ctx context.Context, req entity.OidcStartReq) (*entity.OidcStartRes, error) {
+	res := &entity.OidcStartRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+		stateData, state, err := entity.NewOidcStateData(req)
+		if err != nil {
+			return err
+		}
+
+		// the url is built first, so an unknown provider does not leave a state behind
+		authorizationUrl, err := r.outport.OidcAuthorizationUrl(ctx, entity.OidcAuthorizationUrlReq{
+			Provider:     req.Provider,
+			State:        state,
+			Nonce:        stateData.Nonce,
+			CodeVerifier: stateData.CodeVerifier,
+		})
+		if err != nil {
+			return entity.OidcProviderError.Var(err.Error())
+		}
+
+		err = r.outport.CreateOidcState(ctx, *stateData)
+		if err != nil {
+			return err
+		}
+
+		res.AuthorizationUrl = authorizationUrl
+		res.ExpiredAt = stateData.ExpiredAt
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
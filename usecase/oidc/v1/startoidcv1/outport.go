@@ -0,0 +1,13 @@
+package startoidcv1
+
+import (
+	"backend_base_app/domain/service"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	service.IdentityProviderService
+	apibaseappgateway.OidcStateRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,120 @@
+package completepasswordlessv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"errors"
+	"time"
+)
+
+type apibaseappcompletepasswordlessInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappcompletepasswordlessInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute login like the password login, Member is not logged in yet when TwoFactorRequired
+func (r *apibaseappcompletepasswordlessInteractor) Execute(ctx context.Context, req entity.CompletePasswordlessReq) (*entity.MemberLoginRes, error) {
+	response := &entity.MemberLoginRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		challenge, err := r.verifyChallenge(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		// the link or code is used once, even when the login is refused below
+		err = r.outport.UsePasswordlessChallenge(ctx, challenge.ID)
+		if err != nil {
+			return err
+		}
+
+		member, err := r.outport.FindOneMemberDataById(ctx, challenge.MemberId)
+		if err != nil {
+			return err
+		}
+
+		if member.IsSuspend {
+			return entity.NewMyError("Account is Suspended")
+		}
+		if !req.Config.AllowMemberType(member.MemberType) {
+			return entity.PasswordlessNotAllowed.Var(member.MemberType)
+		}
+
+		err = member.CheckVerified(req.GetRequiredVerification(member.MemberType))
+		if err != nil {
+			return err
+		}
+
+		twoFactor, err := r.outport.FindOneTwoFactorByMemberId(ctx, member.ID)
+		if err != nil && !errors.Is(err, entity.TwoFactorNotEnrolled) {
+			return err
+		}
+		if twoFactor != nil && twoFactor.IsEnabled {
+			response.Member = *member
+			response.TwoFactorRequired = true
+			return nil
+		}
+
+		res, err := r.outport.CompleteMemberLogin(ctx, *member, req.DeviceId, req.TokenBroadcast)
+		if err != nil {
+			return err
+		}
+
+		response.Member = *res
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// verifyChallenge return PasswordlessChallengeInvalid for any wrong token, username or code,
+// every code attempt count against the challenge
+func (r *apibaseappcompletepasswordlessInteractor) verifyChallenge(ctx context.Context, req entity.CompletePasswordlessReq) (*entity.PasswordlessChallengeData, error) {
+	now := time.Now()
+
+	if req.Token != "" {
+		id, ok := entity.PasswordlessChallengeIdOf(req.Token)
+		if !ok {
+			return nil, entity.PasswordlessChallengeInvalid
+		}
+
+		challenge, err := r.outport.FindOnePasswordlessChallengeById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !challenge.VerifyLink(req.Token, req.Config, now) {
+			return nil, entity.PasswordlessChallengeInvalid
+		}
+
+		return challenge, nil
+	}
+
+	member, err := r.outport.FindOneMemberDataByUsername(ctx, req.Username)
+	if err != nil {
+		log.Error(ctx, err.Error())
+		return nil, entity.PasswordlessChallengeInvalid
+	}
+
+	// the attempt is counted before the code is compared, the used challenge is removed after
+	challenge, err := r.outport.AttemptPasswordlessChallengeCode(ctx, member.ID, req.Config.MaxAttempt, now)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.VerifyCode(req.Code, req.Config, now) {
+		return nil, entity.PasswordlessChallengeInvalid
+	}
+
+	return challenge, nil
+}
@@ -0,0 +1,13 @@
+package completepasswordlessv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.PasswordlessChallengeRepo
+	apibaseappgateway.TwoFactorRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,67 @@
+package startpasswordlessv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"errors"
+	"time"
+)
+
+type apibaseappstartpasswordlessInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappstartpasswordlessInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute does not tell whether the member can login without password, the caller always get a success response
+func (r *apibaseappstartpasswordlessInteractor) Execute(ctx context.Context, req entity.StartPasswordlessReq) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		member, err := r.outport.FindOneMemberDataByUsername(ctx, req.Username)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			return nil
+		}
+
+		if !req.Config.AllowMemberType(member.MemberType) {
+			log.Error(ctx, entity.PasswordlessNotAllowed.Var(member.MemberType).Error())
+			return nil
+		}
+		if member.IsSuspend {
+			log.Error(ctx, "Account is Suspended")
+			return nil
+		}
+
+		now := time.Now()
+
+		previous, err := r.outport.FindOnePasswordlessChallengeByMemberId(ctx, member.ID)
+		if err != nil && !errors.Is(err, entity.PasswordlessChallengeInvalid) {
+			return err
+		}
+		if previous != nil {
+			if err := previous.CheckResend(req.Config, now); err != nil {
+				log.Error(ctx, err.Error())
+				return nil
+			}
+		}
+
+		challenge, secret, err := entity.NewPasswordlessChallengeData(*member, req.Channel, req.Config, now)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			return nil
+		}
+
+		err = r.outport.SavePasswordlessChallenge(ctx, *challenge)
+		if err != nil {
+			return err
+		}
+
+		return r.outport.SendNotification(ctx, challenge.ToNotificationData(secret, req.Config))
+	})
+}
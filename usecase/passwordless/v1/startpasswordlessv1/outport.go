@@ -0,0 +1,14 @@
+package startpasswordlessv1
+
+import (
+	"backend_base_app/domain/service"
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	service.NotificationService
+	apibaseappgateway.CreateMemberDataRepo
+	apibaseappgateway.PasswordlessChallengeRepo
+	dbhelpers.WithoutTransactionDB
+}
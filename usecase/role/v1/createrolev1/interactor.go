@@ -0,0 +1,76 @@
+package createrolev1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapprolecreateInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapprolecreateInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapprolecreateInteractor) Execute(ctx context.Context, req entity.CreateRoleData) (*entity.RoleData, error) {
+	res := &entity.RoleData{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		roleObj, err := entity.NewRoleData(req)
+		if err != nil {
+			return err
+		}
+
+		err = r.validatePermissions(ctx, roleObj.Permissions)
+		if err != nil {
+			return err
+		}
+
+		err = entity.ValidateGrantedPermissions(req.GrantedPermissions, roleObj.Permissions)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.CreateRole(ctx, *roleObj)
+		if err != nil {
+			return err
+		}
+
+		res = roleObj
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *apibaseapprolecreateInteractor) validatePermissions(ctx context.Context, permissions []string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	res, err := r.outport.FindAllPermissionByIds(ctx, permissions)
+	if err != nil {
+		return err
+	}
+
+	registered := map[string]bool{}
+	for _, permission := range res {
+		registered[permission.ID] = true
+	}
+	for _, permission := range permissions {
+		if !registered[permission] {
+			return entity.PermissionNotFound.Var(permission)
+		}
+	}
+
+	return nil
+}
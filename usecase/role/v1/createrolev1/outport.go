@@ -0,0 +1,11 @@
+package createrolev1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.RoleRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,40 @@
+package deleterolev1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapproledeleteInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapproledeleteInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapproledeleteInteractor) Execute(ctx context.Context, req entity.DeleteRoleReq) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		for _, role := range entity.DefaultRoles {
+			if role.Name == req.ID {
+				return entity.RoleIsProtected.Var(req.ID)
+			}
+		}
+
+		roleObj, err := r.outport.FindOneRoleById(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		err = entity.ValidateGrantedPermissions(req.GrantedPermissions, roleObj.Permissions)
+		if err != nil {
+			return err
+		}
+
+		return r.outport.DeleteRole(ctx, req.ID)
+	})
+}
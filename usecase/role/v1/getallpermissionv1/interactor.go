@@ -0,0 +1,35 @@
+package getallpermissionv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapppermissiongetallInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapppermissiongetallInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapppermissiongetallInteractor) Execute(ctx context.Context) ([]entity.PermissionData, error) {
+	var response = []entity.PermissionData{}
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, err := r.outport.FindAllPermission(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, permission := range res {
+			response = append(response, *permission)
+		}
+
+		return nil
+	})
+	return response, err
+}
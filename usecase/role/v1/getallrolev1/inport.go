@@ -0,0 +1,10 @@
+package getallrolev1
+
+import (
+	"backend_base_app/domain/entity"
+	"context"
+)
+
+type Inport interface {
+	Execute(ctx context.Context, req entity.BaseReqFind) ([]entity.RoleData, int64, error)
+}
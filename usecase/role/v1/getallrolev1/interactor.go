@@ -0,0 +1,38 @@
+package getallrolev1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapprolegetallInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapprolegetallInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapprolegetallInteractor) Execute(ctx context.Context, req entity.BaseReqFind) ([]entity.RoleData, int64, error) {
+	var response = []entity.RoleData{}
+	var totalRecords = int64(-1)
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		res, count, err := r.outport.FindAllRole(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, role := range res {
+			response = append(response, *role)
+		}
+
+		totalRecords = count
+
+		return nil
+	})
+	return response, totalRecords, err
+}
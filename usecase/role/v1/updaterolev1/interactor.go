@@ -0,0 +1,99 @@
+package updaterolev1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+)
+
+type apibaseapproleupdateInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapproleupdateInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseapproleupdateInteractor) Execute(ctx context.Context, req entity.UpdateRoleData) (*entity.UpdateRoleRes, error) {
+	res := &entity.UpdateRoleRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		roleObj, err := r.outport.FindOneRoleById(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		res.Previous = *roleObj
+
+		// a role holding more permissions than the member can not be changed, superadmin hold PermissionAll
+		err = entity.ValidateGrantedPermissions(req.GrantedPermissions, roleObj.Permissions)
+		if err != nil {
+			return err
+		}
+
+		if req.Name != nil {
+			if len(*req.Name) == 0 {
+				return entity.RoleNameMustNotEmpty
+			}
+			roleObj.Name = *req.Name
+		}
+		if req.Description != nil {
+			roleObj.Description = *req.Description
+		}
+		if req.Permissions != nil {
+			// superadmin must always keep every permission
+			if roleObj.ID == entity.RoleSuperadmin {
+				return entity.RoleIsProtected.Var(roleObj.ID)
+			}
+
+			err = r.validatePermissions(ctx, req.Permissions)
+			if err != nil {
+				return err
+			}
+			err = entity.ValidateGrantedPermissions(req.GrantedPermissions, req.Permissions)
+			if err != nil {
+				return err
+			}
+			roleObj.Permissions = req.Permissions
+		}
+
+		role, err := r.outport.UpdateRole(ctx, *roleObj)
+		if err != nil {
+			return err
+		}
+
+		res.Role = *role
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *apibaseapproleupdateInteractor) validatePermissions(ctx context.Context, permissions []string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	res, err := r.outport.FindAllPermissionByIds(ctx, permissions)
+	if err != nil {
+		return err
+	}
+
+	registered := map[string]bool{}
+	for _, permission := range res {
+		registered[permission.ID] = true
+	}
+	for _, permission := range permissions {
+		if !registered[permission] {
+			return entity.PermissionNotFound.Var(permission)
+		}
+	}
+
+	return nil
+}
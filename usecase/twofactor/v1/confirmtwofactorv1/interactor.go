@@ -0,0 +1,59 @@
+package confirmtwofactorv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+	"time"
+)
+
+type apibaseappconfirmtwofactorInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappconfirmtwofactorInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute enable the enrollment with its first code, the recovery codes are only returned here
+func (r *apibaseappconfirmtwofactorInteractor) Execute(ctx context.Context, req entity.ConfirmTwoFactorReq) (*entity.ConfirmTwoFactorRes, error) {
+	response := &entity.ConfirmTwoFactorRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		twoFactor, err := r.outport.FindOneTwoFactorByMemberId(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+		if twoFactor.IsEnabled {
+			return entity.TwoFactorAlreadyEnabled
+		}
+
+		now := time.Now()
+		counter, ok := twoFactor.VerifyTotp(req.Code, req.Config, now)
+		if !ok {
+			return entity.TwoFactorCodeInvalid
+		}
+
+		recoveryCodes, err := twoFactor.Enable(counter, req.Config, now)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.SaveTwoFactor(ctx, *twoFactor)
+		if err != nil {
+			return err
+		}
+
+		response.RecoveryCodes = recoveryCodes
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,44 @@
+package disabletwofactorv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+	"time"
+)
+
+type apibaseappdisabletwofactorInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappdisabletwofactorInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute require a totp or recovery code, a stolen access token alone can not remove the second factor
+func (r *apibaseappdisabletwofactorInteractor) Execute(ctx context.Context, req entity.DisableTwoFactorReq) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		twoFactor, err := r.outport.FindOneTwoFactorByMemberId(ctx, req.MemberId)
+		if err != nil {
+			return err
+		}
+		if !twoFactor.IsEnabled {
+			return entity.TwoFactorNotEnrolled
+		}
+
+		codeUse, ok := twoFactor.VerifyCode(req.Code, req.Config, time.Now())
+		if !ok {
+			return entity.TwoFactorCodeInvalid
+		}
+
+		err = r.outport.UseTwoFactorCode(ctx, codeUse)
+		if err != nil {
+			return err
+		}
+
+		return r.outport.DeleteTwoFactor(ctx, req.MemberId)
+	})
+}
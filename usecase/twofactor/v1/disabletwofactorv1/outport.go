@@ -0,0 +1,11 @@
+package disabletwofactorv1
+
+import (
+	"backend_base_app/gateway/apibaseappgateway"
+	"backend_base_app/shared/dbhelpers"
+)
+
+type Outport interface {
+	apibaseappgateway.TwoFactorRepo
+	dbhelpers.WithoutTransactionDB
+}
@@ -0,0 +1,54 @@
+package enrolltwofactorv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"context"
+	"errors"
+)
+
+type apibaseappenrolltwofactorInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappenrolltwofactorInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute replace the secret of an enrollment which is not confirmed yet
+func (r *apibaseappenrolltwofactorInteractor) Execute(ctx context.Context, req entity.EnrollTwoFactorReq) (*entity.EnrollTwoFactorRes, error) {
+	response := &entity.EnrollTwoFactorRes{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		existing, err := r.outport.FindOneTwoFactorByMemberId(ctx, req.MemberId)
+		if err != nil && !errors.Is(err, entity.TwoFactorNotEnrolled) {
+			return err
+		}
+		if existing != nil && existing.IsEnabled {
+			return entity.TwoFactorAlreadyEnabled
+		}
+
+		twoFactor, err := entity.NewTwoFactorData(req.MemberId)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.SaveTwoFactor(ctx, *twoFactor)
+		if err != nil {
+			return err
+		}
+
+		res := twoFactor.ToEnrollRes(req.Config.Issuer, req.AccountName)
+		response = &res
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
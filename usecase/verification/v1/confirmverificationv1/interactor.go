@@ -0,0 +1,67 @@
+package confirmverificationv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"time"
+)
+
+type apibaseappconfirmverificationInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseappconfirmverificationInteractor{
+		outport: outputPort,
+	}
+}
+
+func (r *apibaseappconfirmverificationInteractor) Execute(ctx context.Context, req entity.ConfirmVerificationReq) (*entity.MemberDataShown, error) {
+	response := &entity.MemberDataShown{}
+
+	err := dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		member, err := r.outport.FindOneMemberDataByUsername(ctx, req.Username)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			return entity.VerificationCodeInvalid
+		}
+
+		// the attempt is counted before the code is compared, so parallel guesses stop at max_attempt
+		verificationCode, err := r.outport.AttemptVerificationCode(ctx, entity.VerificationCodeId(member.ID, req.Channel), req.Config.MaxAttempt, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if !verificationCode.IsCodeUsable(req.Config, time.Now()) || !verificationCode.MatchCode(req.Code) {
+			return entity.VerificationCodeInvalid
+		}
+
+		verificationCode.Consume()
+		err = r.outport.SaveVerificationCode(ctx, *verificationCode)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.UpdateMemberVerified(ctx, member.ID, req.Channel, verificationCode.Target)
+		if err != nil {
+			return err
+		}
+
+		res, err := r.outport.FindOneMemberDataById(ctx, member.ID)
+		if err != nil {
+			return err
+		}
+
+		response = res
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,71 @@
+package requestverificationv1
+
+import (
+	"backend_base_app/domain/entity"
+	"backend_base_app/shared/dbhelpers"
+	"backend_base_app/shared/log"
+	"context"
+	"errors"
+	"time"
+)
+
+type apibaseapprequestverificationInteractor struct {
+	outport Outport
+}
+
+func NewUsecase(outputPort Outport) Inport {
+	return &apibaseapprequestverificationInteractor{
+		outport: outputPort,
+	}
+}
+
+// Execute send a new code, an unknown username, a member without the target, an already verified
+// or a too frequent request get the same success response so the member state is not revealed
+func (r *apibaseapprequestverificationInteractor) Execute(ctx context.Context, req entity.RequestVerificationReq) error {
+	return dbhelpers.WithoutTransaction(ctx, r.outport, func(ctx context.Context) error {
+
+		member, err := r.outport.FindOneMemberDataByUsername(ctx, req.Username)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			return nil
+		}
+
+		target, verified := member.VerificationTarget(req.Channel)
+		if target == "" {
+			log.Error(ctx, entity.VerificationTargetNotFound.Var(req.Channel).Error())
+			return nil
+		}
+		if verified {
+			log.Error(ctx, entity.MemberAlreadyVerified.Var(req.Channel).Error())
+			return nil
+		}
+
+		verificationCode, err := r.outport.FindOneVerificationCodeById(ctx, entity.VerificationCodeId(member.ID, req.Channel))
+		if err != nil {
+			if !errors.Is(err, entity.VerificationCodeInvalid) {
+				return err
+			}
+			newVerificationCode := entity.NewVerificationCodeData(member.ID, req.Channel)
+			verificationCode = &newVerificationCode
+		}
+
+		now := time.Now()
+		err = verificationCode.CheckRequest(req.Config, now)
+		if err != nil {
+			log.Error(ctx, err.Error())
+			return nil
+		}
+
+		code, err := verificationCode.Renew(target, req.Config, now)
+		if err != nil {
+			return err
+		}
+
+		err = r.outport.SaveVerificationCode(ctx, *verificationCode)
+		if err != nil {
+			return err
+		}
+
+		return r.outport.SendNotification(ctx, verificationCode.ToNotificationData(code))
+	})
+}